@@ -0,0 +1,86 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Errors
+var (
+	ErrAmbiguousOutcome = errors.New("outcome of request is unknown")
+)
+
+// AmbiguousOutcomeError is returned for POST calls that failed after the request may have reached Seller Center,
+// e.g. on a connection reset while waiting for the response or on a 502 or 504 from a gateway. The mutation may
+// have been applied, so it must not be repeated blindly. Check for an earlier success first, e.g. for a feed
+// created since SentAt or for the current status of the order items. The same holds when the context of the
+// call ends after such a response, the error then wraps the error of the context.
+//
+// It matches ErrAmbiguousOutcome with errors.Is and unwraps to the error of the last attempt.
+type AmbiguousOutcomeError struct {
+	Action   string
+	SentAt   time.Time
+	Attempts int
+	Err      error
+}
+
+func newAmbiguousOutcomeError(action string, sentAt time.Time, attempts int, err error) *AmbiguousOutcomeError {
+	return &AmbiguousOutcomeError{
+		Action:   action,
+		SentAt:   sentAt,
+		Attempts: attempts,
+		Err:      err,
+	}
+}
+
+func (e *AmbiguousOutcomeError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", ErrAmbiguousOutcome, e.Action, e.Err)
+}
+
+func (e *AmbiguousOutcomeError) Is(target error) bool {
+	return target == ErrAmbiguousOutcome
+}
+
+func (e *AmbiguousOutcomeError) Unwrap() error {
+	return e.Err
+}
+
+// isAmbiguousOutcome reports whether a POST that ended without a usable response may have been applied: when no
+// response was read and err does not prove that the request never reached Seller Center, or when a gateway sent
+// the response instead of Seller Center. Both the retry loop and a context that ended during the call use it.
+func isAmbiguousOutcome(method string, response *http.Response, err error) bool {
+	if method != MethodPOST {
+		return false
+	}
+
+	if response == nil {
+		return isAmbiguousError(err)
+	}
+
+	return isAmbiguousStatus(response.StatusCode)
+}
+
+// isAmbiguousError reports whether a request failing with err may have reached Seller Center. Only errors of
+// establishing the connection prove that it did not.
+func isAmbiguousError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+
+	return true
+}
+
+// isAmbiguousStatus reports whether a response with the status may have been sent by a gateway after Seller
+// Center processed the request.
+func isAmbiguousStatus(statusCode int) bool {
+	return statusCode == http.StatusBadGateway || statusCode == http.StatusGatewayTimeout
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_Client_Returns_Ambiguous_Outcome_For_Post_On_Gateway_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer server.Close()
+
+	c := createTestClient(server.URL, WithRetryPolicy(NewExponentialBackoffRetryPolicy()))
+
+	_, err := c.Call(NewGenericRequest("ProductCreate", MethodPOST))
+
+	var outcome *AmbiguousOutcomeError
+	if !errors.As(err, &outcome) || outcome.Action != "ProductCreate" || outcome.Attempts != 1 || outcome.SentAt.IsZero() {
+		t.Fatalf("expected AmbiguousOutcomeError of first attempt, actual `%v`.", err)
+	}
+
+	if !errors.Is(err, ErrAmbiguousOutcome) || !errors.Is(err, NoHttp200ResponseError) {
+		t.Fatalf("expected error to match ErrAmbiguousOutcome and NoHttp200ResponseError, actual `%v`.", err)
+	}
+}
+
+func Test_Client_Returns_Ambiguous_Outcome_For_Post_On_Dropped_Connection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	c := createTestClient(server.URL)
+
+	if _, err := c.Call(NewGenericRequest("ProductUpdate", MethodPOST)); !errors.Is(err, ErrAmbiguousOutcome) {
+		t.Fatalf("expected ErrAmbiguousOutcome, actual `%v`.", err)
+	}
+}
+
+func Test_Client_Does_Not_Return_Ambiguous_Outcome_For_Unsent_Or_Get_Requests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	policy := NewExponentialBackoffRetryPolicy()
+	policy.MaxAttempts = 1
+	c := createTestClient(server.URL, WithRetryPolicy(policy))
+
+	if _, err := c.Call(NewGenericRequest("GetOrders", MethodGET)); err == nil || errors.Is(err, ErrAmbiguousOutcome) {
+		t.Fatalf("expected plain error for GET, actual `%v`.", err)
+	}
+
+	server.Close()
+
+	if _, err := c.Call(NewGenericRequest("ProductCreate", MethodPOST)); err == nil || errors.Is(err, ErrAmbiguousOutcome) {
+		t.Fatalf("expected plain error for refused connection, actual `%v`.", err)
+	}
+}
+
+type cancelingTransport struct {
+	cancel context.CancelFunc
+}
+
+func (ct cancelingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := http.DefaultTransport.RoundTrip(request)
+	ct.cancel()
+
+	return response, err
+}
+
+func Test_Client_Applies_Ambiguous_Outcome_Rule_When_Context_Ends_After_Response(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "feed-1", "RequestAction": "ProductCreate", "ResponseType": "", "Timestamp": ""}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := createTestClient(server.URL, WithTransport(cancelingTransport{cancel: cancel}))
+
+	response, err := CallContext(ctx, c, NewGenericRequest("ProductCreate", MethodPOST))
+	if err != nil || response.IsError() {
+		t.Fatalf("expected the response that was read, actual `%v`.", err)
+	}
+
+	status = http.StatusBadGateway
+	ctx, cancel = context.WithCancel(context.Background())
+	c = createTestClient(server.URL, WithTransport(cancelingTransport{cancel: cancel}))
+
+	if _, err := CallContext(ctx, c, NewGenericRequest("ProductCreate", MethodPOST)); !errors.Is(err, context.Canceled) || !errors.Is(err, ErrAmbiguousOutcome) {
+		t.Fatalf("expected AmbiguousOutcomeError for gateway error, as without canceled context, actual `%v`.", err)
+	}
+
+	status = http.StatusBadRequest
+	ctx, cancel = context.WithCancel(context.Background())
+	c = createTestClient(server.URL, WithTransport(cancelingTransport{cancel: cancel}))
+
+	if _, err := CallContext(ctx, c, NewGenericRequest("ProductCreate", MethodPOST)); !errors.Is(err, context.Canceled) || errors.Is(err, ErrAmbiguousOutcome) {
+		t.Fatalf("expected plain context.Canceled, actual `%v`.", err)
+	}
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	DefaultUserHeader = "X-Sellercenter-User"
+	DefaultKeyHeader  = "X-Sellercenter-Key"
+)
+
+// Authenticator adds the credentials of the client to an outgoing request. It is called for every attempt,
+// after the query and the headers of the request have been set.
+type Authenticator interface {
+	Authenticate(ctx context.Context, request *http.Request) error
+}
+
+// QuerySigningAuthenticator signs requests the way Seller Center expects, the default of the client: it sets
+// UserID, Timestamp and the HMAC-SHA256 Signature of the canonical query in the query of the request.
+//
+// The credentials of Credentials are used if set, User and Key otherwise. Passed to WithAuthenticator, it
+// compensates the clock skew measured by the client; used on its own, it signs with the time of Clock as it is.
+type QuerySigningAuthenticator struct {
+	User        string
+	Key         string
+	Credentials CredentialsProvider
+	Clock       Clock
+
+	// skew corrects the clock of the default authenticator of a client by the measured clock skew.
+	skew *clockSkew
+}
+
+func NewQuerySigningAuthenticator(user string, key string) *QuerySigningAuthenticator {
+	return &QuerySigningAuthenticator{
+		User:  user,
+		Key:   key,
+		Clock: SystemClock,
+	}
+}
+
+func (a *QuerySigningAuthenticator) Authenticate(ctx context.Context, request *http.Request) error {
+	credentials, err := retrieveCredentials(ctx, a.Credentials, a.User, a.Key)
+	if err != nil {
+		return err
+	}
+
+	clock := a.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	params := request.URL.Query()
+	NewSigner(credentials.User, credentials.Key).Sign(params, clock.Now().Add(a.skew.get()))
+
+	request.URL.RawQuery = encodeQuery(params)
+
+	return nil
+}
+
+// HeaderAuthenticator sends the user and the key in headers instead of signing the query, for API gateways
+// that authenticate requests themselves. Empty header names default to DefaultUserHeader and DefaultKeyHeader.
+//
+// The credentials of Credentials are used if set, User and Key otherwise.
+type HeaderAuthenticator struct {
+	User        string
+	Key         string
+	Credentials CredentialsProvider
+	UserHeader  string
+	KeyHeader   string
+}
+
+func NewHeaderAuthenticator(user string, key string) *HeaderAuthenticator {
+	return &HeaderAuthenticator{
+		User:       user,
+		Key:        key,
+		UserHeader: DefaultUserHeader,
+		KeyHeader:  DefaultKeyHeader,
+	}
+}
+
+func (a *HeaderAuthenticator) Authenticate(ctx context.Context, request *http.Request) error {
+	credentials, err := retrieveCredentials(ctx, a.Credentials, a.User, a.Key)
+	if err != nil {
+		return err
+	}
+
+	userHeader, keyHeader := a.UserHeader, a.KeyHeader
+	if userHeader == "" {
+		userHeader = DefaultUserHeader
+	}
+	if keyHeader == "" {
+		keyHeader = DefaultKeyHeader
+	}
+
+	request.Header.Set(userHeader, credentials.User)
+	request.Header.Set(keyHeader, credentials.Key)
+
+	return nil
+}
+
+// TokenFunc returns the token of a request, e.g. from a cache refreshed by an OAuth2 client.
+type TokenFunc func(ctx context.Context) (string, error)
+
+// StaticToken returns a TokenFunc that always returns the token.
+func StaticToken(token string) TokenFunc {
+	return func(context.Context) (string, error) {
+		return token, nil
+	}
+}
+
+// BearerAuthenticator sets an `Authorization: Bearer` header with the token of Token.
+type BearerAuthenticator struct {
+	Token TokenFunc
+}
+
+func NewBearerAuthenticator(token TokenFunc) *BearerAuthenticator {
+	return &BearerAuthenticator{Token: token}
+}
+
+func (a *BearerAuthenticator) Authenticate(ctx context.Context, request *http.Request) error {
+	token, err := a.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+func retrieveCredentials(ctx context.Context, provider CredentialsProvider, user string, key string) (Credentials, error) {
+	if provider == nil {
+		return Credentials{User: user, Key: key}, nil
+	}
+
+	return provider.Retrieve(ctx)
+}
+
+// newDefaultAuthenticator signs with the credentials of the config or of the provider and compensates clock skew.
+func newDefaultAuthenticator(config clientConfig, credentials CredentialsProvider, clock Clock, skew *clockSkew) Authenticator {
+	return &QuerySigningAuthenticator{
+		User:        config.User,
+		Key:         config.Key,
+		Credentials: credentials,
+		Clock:       clock,
+		skew:        skew,
+	}
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const authenticatedBody = `{"SuccessResponse": {"Head": {"RequestId": "", "RequestAction": "GetBrands", "ResponseType": "", "Timestamp": ""}, "Body": ""}}`
+
+func Test_Client_Authenticates_With_Headers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get(fieldSignature) != "" || r.URL.Query().Get(fieldUserId) != "" {
+			t.Errorf("expected unsigned query, actual `%s`.", r.URL.RawQuery)
+		}
+
+		if r.Header.Get("X-Api-User") != "abc@sellercenter.net" || r.Header.Get(DefaultKeyHeader) != "1234567890" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(authenticatedBody))
+	}))
+	defer server.Close()
+
+	authenticator := NewHeaderAuthenticator("abc@sellercenter.net", "1234567890")
+	authenticator.UserHeader = "X-Api-User"
+
+	c := createTestClient(server.URL, WithAuthenticator(authenticator))
+
+	if response, err := c.Call(NewGenericRequest("GetBrands", MethodGET)); err != nil || response.IsError() {
+		t.Fatalf("expected success response, actual `%v` `%v`.", response, err)
+	}
+}
+
+func Test_Client_Authenticates_With_Bearer_Token(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(authenticatedBody))
+	}))
+	defer server.Close()
+
+	c := createTestClient(server.URL, WithAuthenticator(NewBearerAuthenticator(StaticToken("secret-token"))))
+	if response, err := c.Call(NewGenericRequest("GetBrands", MethodGET)); err != nil || response.IsError() {
+		t.Fatalf("expected success response, actual `%v` `%v`.", response, err)
+	}
+
+	tokenErr := errors.New("token expired")
+	c = createTestClient(server.URL, WithAuthenticator(NewBearerAuthenticator(func(context.Context) (string, error) {
+		return "", tokenErr
+	})))
+	if _, err := c.Call(NewGenericRequest("GetBrands", MethodGET)); err != tokenErr {
+		t.Fatalf("expected token error, actual `%v`.", err)
+	}
+}
+
+type countingAuthenticator struct {
+	calls int32
+	next  Authenticator
+}
+
+func (a *countingAuthenticator) Authenticate(ctx context.Context, request *http.Request) error {
+	atomic.AddInt32(&a.calls, 1)
+
+	return a.next.Authenticate(ctx, request)
+}
+
+func Test_Client_Authenticates_Every_Attempt_With_Query_Signing(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(SignatureMiddleware(func(user string) (string, bool) {
+		return "1234567890", user == "abc@sellercenter.net"
+	}, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(authenticatedBody))
+	})))
+	defer server.Close()
+
+	policy := NewExponentialBackoffRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	authenticator := &countingAuthenticator{next: NewQuerySigningAuthenticator("abc@sellercenter.net", "1234567890")}
+	c := createTestClient(server.URL, WithAuthenticator(authenticator), WithRetryPolicy(policy))
+
+	if response, err := c.Call(NewGenericRequest("GetBrands", MethodGET)); err != nil || response.IsError() {
+		t.Fatalf("expected success response, actual `%v` `%v`.", response, err)
+	}
+
+	if calls := atomic.LoadInt32(&authenticator.calls); calls != 2 {
+		t.Fatalf("expected 2 authenticated attempts, actual %d.", calls)
+	}
+}
+
+func Test_Client_Compensates_Clock_Skew_With_Custom_Query_Signing_Authenticator(t *testing.T) {
+	localNow := time.Date(2018, 7, 6, 13, 37, 57, 0, time.UTC)
+
+	timestamps := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamps = append(timestamps, r.URL.Query().Get("Timestamp"))
+		fmt.Fprintf(w, `{"SuccessResponse": {"Head": {"RequestId": "1", "Timestamp": "%s"}, "Body": ""}}`, localNow.Add(time.Hour).Format(ResponseTimestampFormat))
+	}))
+	defer server.Close()
+
+	authenticator := NewQuerySigningAuthenticator("abc@sellercenter.net", "1234567890")
+	authenticator.Clock = &fakeNow{now: localNow}
+
+	c := createTestClient(server.URL, WithAuthenticator(authenticator), WithClock(authenticator.Clock))
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Call(NewGenericRequest("GetOrders", MethodGET)); err != nil {
+			t.Fatalf("expected error to be nil, actual `%s`.", err)
+		}
+	}
+
+	if expected := []string{"2018-07-06T13:37:57Z", "2018-07-06T14:37:57Z"}; !reflect.DeepEqual(expected, timestamps) {
+		t.Fatalf("unexpected signed timestamps. expected: `%v` - actual: `%v`.", expected, timestamps)
+	}
+
+	if authenticator.skew != nil {
+		t.Fatal("expected the authenticator passed to the client not to be changed.")
+	}
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// Operation is a single call of a batch, e.g. GetOrderItems for an order. Key identifies it in the results.
+type Operation struct {
+	Key string
+	Run func(ctx context.Context) (interface{}, error)
+}
+
+// BatchResult is the outcome of an operation. Operations that were not started because the context was done get
+// the error of the context, those skipped because the batch stopped on an error get context.Canceled.
+type BatchResult struct {
+	Key   string
+	Value interface{}
+	Err   error
+}
+
+// BatchResults are the results of a batch in the order of its operations.
+type BatchResults []BatchResult
+
+// Get returns the result of the operation with the key.
+func (r BatchResults) Get(key string) (BatchResult, bool) {
+	for _, result := range r {
+		if result.Key == key {
+			return result, true
+		}
+	}
+
+	return BatchResult{}, false
+}
+
+// Errors returns the errors of the failed operations by key.
+func (r BatchResults) Errors() map[string]error {
+	errs := map[string]error{}
+	for _, result := range r {
+		if result.Err != nil {
+			errs[result.Key] = result.Err
+		}
+	}
+
+	return errs
+}
+
+// BatchProgress is passed to the progress callback of a Batch after every finished operation.
+type BatchProgress struct {
+	Total  int
+	Done   int
+	Failed int
+	Last   BatchResult
+}
+
+// Batch runs operations with bounded concurrency instead of a goroutine per call. Operations calling the same
+// client share its rate limiter, so the limiter rather than Concurrency decides the rate of requests.
+type Batch struct {
+	// Concurrency is the maximum number of operations running at once. Zero or less runs all of them at once.
+	Concurrency int
+	// StopOnError skips the operations not started yet as soon as an operation fails. Operations already running
+	// keep the context of Run and finish, so a POST in flight is not turned into an ambiguous outcome.
+	StopOnError bool
+	// Progress is called after every finished operation, one call at a time.
+	Progress func(BatchProgress)
+}
+
+func NewBatch(concurrency int) *Batch {
+	return &Batch{Concurrency: concurrency}
+}
+
+// Run runs the operations and waits for all of them. When ctx is done, operations not started yet are skipped.
+func (b *Batch) Run(ctx context.Context, operations []Operation) BatchResults {
+	results := make(BatchResults, len(operations))
+	if len(operations) == 0 {
+		return results
+	}
+
+	workers := b.Concurrency
+	if workers <= 0 || workers > len(operations) {
+		workers = len(operations)
+	}
+
+	indexes := make(chan int)
+	progress := &batchProgress{batch: b, stopped: make(chan struct{}), state: BatchProgress{Total: len(operations)}}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				result := &results[i]
+				result.Key = operations[i].Key
+
+				if progress.isStopped() {
+					result.Err = context.Canceled
+				} else if err := ctx.Err(); err != nil {
+					result.Err = err
+				} else {
+					result.Value, result.Err = operations[i].Run(ctx)
+				}
+
+				progress.observe(*result)
+			}
+		}()
+	}
+
+	for i := range operations {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	return results
+}
+
+type batchProgress struct {
+	batch *Batch
+
+	// stopped is closed when the batch stops on an error, without canceling the context of running operations.
+	stopped chan struct{}
+
+	mu    sync.Mutex
+	state BatchProgress
+}
+
+func (p *batchProgress) isStopped() bool {
+	select {
+	case <-p.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *batchProgress) observe(result BatchResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.state.Done++
+	p.state.Last = result
+	if result.Err != nil {
+		p.state.Failed++
+
+		if p.batch.StopOnError && !p.isStopped() {
+			close(p.stopped)
+		}
+	}
+
+	if p.batch.Progress != nil {
+		p.batch.Progress(p.state)
+	}
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func createOperations(n int, run func(i int) (interface{}, error)) []Operation {
+	operations := make([]Operation, n)
+	for i := range operations {
+		i := i
+
+		operations[i] = Operation{
+			Key: strconv.Itoa(i),
+			Run: func(ctx context.Context) (interface{}, error) {
+				return run(i)
+			},
+		}
+	}
+
+	return operations
+}
+
+func Test_Batch_Bounds_Concurrency_And_Collects_Results_By_Key(t *testing.T) {
+	var running, maxRunning int32
+	operations := createOperations(20, func(i int) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		if i%5 == 0 {
+			return nil, errors.New("failed " + strconv.Itoa(i))
+		}
+
+		return i * 2, nil
+	})
+
+	var progress []BatchProgress
+	batch := NewBatch(3)
+	batch.Progress = func(p BatchProgress) {
+		progress = append(progress, p)
+	}
+
+	results := batch.Run(context.Background(), operations)
+
+	if max := atomic.LoadInt32(&maxRunning); max > 3 {
+		t.Fatalf("expected at most 3 operations at once, actual %d.", max)
+	}
+
+	if result, ok := results.Get("7"); !ok || result.Value != 14 || result.Err != nil {
+		t.Fatalf("unexpected result `%+v`.", result)
+	}
+
+	if errs := results.Errors(); len(errs) != 4 || errs["15"] == nil {
+		t.Fatalf("expected 4 errors, actual `%v`.", errs)
+	}
+
+	last := progress[len(progress)-1]
+	if len(progress) != 20 || last.Done != 20 || last.Failed != 4 || last.Total != 20 {
+		t.Fatalf("unexpected progress `%+v`.", last)
+	}
+}
+
+func Test_Batch_Stops_On_Error(t *testing.T) {
+	var calls int32
+	operations := createOperations(10, func(i int) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+
+		return nil, errors.New("failed")
+	})
+
+	batch := NewBatch(1)
+	batch.StopOnError = true
+
+	results := batch.Run(context.Background(), operations)
+
+	if calls := atomic.LoadInt32(&calls); calls != 1 {
+		t.Fatalf("expected 1 call, actual %d.", calls)
+	}
+
+	if result, _ := results.Get("9"); !errors.Is(result.Err, context.Canceled) {
+		t.Fatalf("expected skipped operation to fail with context.Canceled, actual `%v`.", result.Err)
+	}
+}
+
+func Test_Batch_Stop_On_Error_Lets_Running_Operations_Finish(t *testing.T) {
+	failed := make(chan struct{})
+	operations := []Operation{
+		{Key: "slow", Run: func(ctx context.Context) (interface{}, error) {
+			<-failed
+
+			// ... give a canceled context the chance to show
+			time.Sleep(10 * time.Millisecond)
+
+			return "done", ctx.Err()
+		}},
+		{Key: "failing", Run: func(ctx context.Context) (interface{}, error) {
+			defer close(failed)
+
+			return nil, errors.New("failed")
+		}},
+		{Key: "skipped", Run: func(ctx context.Context) (interface{}, error) {
+			t.Error("expected operation not to be started after the batch stopped.")
+
+			return nil, nil
+		}},
+	}
+
+	batch := NewBatch(2)
+	batch.StopOnError = true
+
+	results := batch.Run(context.Background(), operations)
+
+	if result, _ := results.Get("slow"); result.Err != nil || result.Value != "done" {
+		t.Fatalf("expected running operation to finish with its context, actual `%+v`.", result)
+	}
+
+	if result, _ := results.Get("skipped"); !errors.Is(result.Err, context.Canceled) {
+		t.Fatalf("expected skipped operation to fail with context.Canceled, actual `%v`.", result.Err)
+	}
+}
+
+func Test_Batch_Skips_Operations_Of_Canceled_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	operations := createOperations(5, func(i int) (interface{}, error) {
+		if i == 1 {
+			cancel()
+		}
+
+		return i, nil
+	})
+
+	results := NewBatch(1).Run(ctx, operations)
+
+	if result, _ := results.Get("1"); result.Err != nil || result.Value != 1 {
+		t.Fatalf("expected running operation to finish, actual `%+v`.", result)
+	}
+
+	if errs := results.Errors(); len(errs) != 3 || !errors.Is(errs["4"], context.Canceled) {
+		t.Fatalf("expected 3 skipped operations, actual `%v`.", errs)
+	}
+}
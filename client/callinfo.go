@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CallInfo describes a single call: the head of the response, how many HTTP attempts it took, the round-trip
+// latency including retries and the HTTP status of the last attempt.
+type CallInfo struct {
+	RequestId     string
+	Timestamp     string
+	ResponseType  string
+	RequestAction string
+	ErrorCode     string
+	Attempts      int
+	Latency       time.Duration
+	StatusCode    int
+}
+
+// CallInfoCollector keeps the CallInfo of the calls made with a context returned by ContextWithCallInfo.
+// It is safe for concurrent use.
+type CallInfoCollector struct {
+	mu    sync.Mutex
+	infos []CallInfo
+}
+
+type callInfoContextKey struct{}
+
+// ContextWithCallInfo returns a copy of ctx that makes the client record a CallInfo for each call into the
+// returned collector, e.g. to get the RequestId of resource calls that return only the model.
+func ContextWithCallInfo(ctx context.Context) (context.Context, *CallInfoCollector) {
+	collector := &CallInfoCollector{}
+
+	return context.WithValue(ctx, callInfoContextKey{}, collector), collector
+}
+
+func callInfoCollectorFromContext(ctx context.Context) *CallInfoCollector {
+	collector, _ := ctx.Value(callInfoContextKey{}).(*CallInfoCollector)
+
+	return collector
+}
+
+// Last returns the CallInfo of the most recent call, false if there was none.
+func (cic *CallInfoCollector) Last() (CallInfo, bool) {
+	cic.mu.Lock()
+	defer cic.mu.Unlock()
+
+	if len(cic.infos) == 0 {
+		return CallInfo{}, false
+	}
+
+	return cic.infos[len(cic.infos)-1], true
+}
+
+// All returns the CallInfo of every call in the order they finished.
+func (cic *CallInfoCollector) All() []CallInfo {
+	cic.mu.Lock()
+	defer cic.mu.Unlock()
+
+	return append([]CallInfo(nil), cic.infos...)
+}
+
+func (cic *CallInfoCollector) add(info CallInfo) {
+	cic.mu.Lock()
+	defer cic.mu.Unlock()
+
+	cic.infos = append(cic.infos, info)
+}
+
+func newCallInfo(request Request, resp Response, stats *callStats, latency time.Duration) CallInfo {
+	info := CallInfo{
+		RequestAction: request.GetRequestParams().Get(fieldAction),
+		Attempts:      stats.attempts,
+		Latency:       latency,
+		StatusCode:    stats.lastStatusCode(),
+	}
+
+	switch head := headObject(resp).(type) {
+	case headSuccessResponse:
+		info.RequestId = head.RequestId
+		info.Timestamp = head.Timestamp
+		info.ResponseType = head.ResponseType
+		info.RequestAction = head.RequestAction
+	case HeadErrorResponse:
+		info.Timestamp = head.Timestamp
+		info.ErrorCode = head.ErrorCode
+		if head.RequestAction != "" {
+			info.RequestAction = head.RequestAction
+		}
+	}
+
+	return info
+}
+
+func headObject(resp Response) interface{} {
+	if resp == nil {
+		return nil
+	}
+
+	return resp.GetHeadObject()
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Can_Collect_Call_Info_From_Context(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "13e55362-3d4d-4e4d-b8d4-3e4d2e5e3a56", "RequestAction": "GetOrders", "ResponseType": "Orders", "Timestamp": "2016-06-22T04:40:14+0200"}, "Body": {}}}`))
+	}))
+	defer server.Close()
+
+	retryPolicy := NewExponentialBackoffRetryPolicy()
+	retryPolicy.BaseDelay = time.Millisecond
+
+	ctx, collector := ContextWithCallInfo(context.Background())
+
+	if _, ok := collector.Last(); ok {
+		t.Fatal("expected no CallInfo before the first call.")
+	}
+
+	_, err := CallContext(ctx, createTestClient(server.URL, WithRetryPolicy(retryPolicy)), NewGenericRequest("GetOrders", MethodGET))
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	info, ok := collector.Last()
+	if !ok {
+		t.Fatal("expected CallInfo after the call.")
+	}
+
+	if info.Latency <= 0 {
+		t.Fatalf("expected positive latency, actual %s.", info.Latency)
+	}
+
+	info.Latency = 0
+	expected := CallInfo{
+		RequestId:     "13e55362-3d4d-4e4d-b8d4-3e4d2e5e3a56",
+		Timestamp:     "2016-06-22T04:40:14+0200",
+		ResponseType:  "Orders",
+		RequestAction: "GetOrders",
+		Attempts:      2,
+		StatusCode:    http.StatusOK,
+	}
+
+	if !reflect.DeepEqual(info, expected) {
+		t.Fatalf("can not collect CallInfo. expected: `%+v` - actual: `%+v`.", expected, info)
+	}
+}
+
+func Test_Fake_Client_Collects_Call_Info(t *testing.T) {
+	ctx, collector := ContextWithCallInfo(context.Background())
+
+	fakeClient := FakeClient{
+		FakeResponse: ErrorResponse{HeadObject: HeadErrorResponse{RequestAction: "GetOrder", ErrorCode: "16"}},
+	}
+
+	fakeClient.CallContext(ctx, NewGenericRequest("GetOrder", MethodGET))
+	fakeClient.CallContext(ctx, NewGenericRequest("GetOrders", MethodGET))
+
+	expected := []CallInfo{
+		{RequestAction: "GetOrder", ErrorCode: "16", Attempts: 1},
+		{RequestAction: "GetOrder", ErrorCode: "16", Attempts: 1},
+	}
+
+	if infos := collector.All(); !reflect.DeepEqual(infos, expected) {
+		t.Fatalf("can not collect CallInfo. expected: `%+v` - actual: `%+v`.", expected, infos)
+	}
+}
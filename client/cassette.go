@@ -0,0 +1,458 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors
+var (
+	ErrUnmatchedInteraction = errors.New("no recorded interaction matches the request")
+	ErrUnusedInteractions   = errors.New("recorded interactions were not used")
+)
+
+// volatileParams differ from run to run and are left out of recorded interactions.
+var volatileParams = []string{fieldSignature, fieldTimestamp, fieldUserId}
+
+// Cassette is the on-disk format of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Action string     `json:"action"`
+	Method string     `json:"method"`
+	Params url.Values `json:"params"`
+	Body   string     `json:"body,omitempty"`
+}
+
+// RecordedResponse holds either a response, with IsError telling an ErrorResponse from a SuccessResponse,
+// or the error returned by the client.
+type RecordedResponse struct {
+	IsError bool            `json:"isError,omitempty"`
+	Head    json.RawMessage `json:"head,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	Error   *RecordedError  `json:"error,omitempty"`
+}
+
+// Kinds of recorded errors. Errors of other kinds are replayed with their message only.
+const (
+	ErrorKindHTTP               = "http"
+	ErrorKindAmbiguousOutcome   = "ambiguousOutcome"
+	ErrorKindTimeout            = "timeout"
+	ErrorKindResponseTooLarge   = "responseTooLarge"
+	ErrorKindCircuitOpen        = "circuitOpen"
+	ErrorKindUnexpectedResponse = "unexpectedResponse"
+	ErrorKindUnsupportedMethod  = "unsupportedMethod"
+	ErrorKindCanceled           = "canceled"
+	ErrorKindDeadlineExceeded   = "deadlineExceeded"
+)
+
+// recordedSentinels are the sentinel errors replayed errors of a kind match with errors.Is, checked in order.
+var recordedSentinels = []struct {
+	kind string
+	err  error
+}{
+	{ErrorKindResponseTooLarge, ErrResponseTooLarge},
+	{ErrorKindCircuitOpen, ErrCircuitOpen},
+	{ErrorKindUnexpectedResponse, NoHttp200ResponseError},
+	{ErrorKindUnsupportedMethod, NotSupportedMethod},
+	{ErrorKindCanceled, context.Canceled},
+	{ErrorKindDeadlineExceeded, context.DeadlineExceeded},
+}
+
+// RecordedError is an error returned by the client. Its kind and the fields of its type are recorded, so that
+// replays return an error matching the same errors.Is and errors.As checks, e.g. an *HTTPError with the
+// status code or an *AmbiguousOutcomeError wrapping the recorded cause.
+type RecordedError struct {
+	Kind       string         `json:"kind,omitempty"`
+	Message    string         `json:"message"`
+	StatusCode int            `json:"statusCode,omitempty"`
+	Status     string         `json:"status,omitempty"`
+	Header     http.Header    `json:"header,omitempty"`
+	Body       string         `json:"body,omitempty"`
+	Action     string         `json:"action,omitempty"`
+	SentAt     *time.Time     `json:"sentAt,omitempty"`
+	Attempts   int            `json:"attempts,omitempty"`
+	Cause      *RecordedError `json:"cause,omitempty"`
+}
+
+func newRecordedError(err error) *RecordedError {
+	recorded := &RecordedError{Message: err.Error()}
+
+	var ambiguousErr *AmbiguousOutcomeError
+	if errors.As(err, &ambiguousErr) {
+		sentAt := ambiguousErr.SentAt
+		recorded.Kind = ErrorKindAmbiguousOutcome
+		recorded.Action = ambiguousErr.Action
+		recorded.SentAt = &sentAt
+		recorded.Attempts = ambiguousErr.Attempts
+		if ambiguousErr.Err != nil {
+			recorded.Cause = newRecordedError(ambiguousErr.Err)
+		}
+
+		return recorded
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		recorded.Kind = ErrorKindHTTP
+		recorded.StatusCode = httpErr.StatusCode
+		recorded.Status = httpErr.Status
+		recorded.Header = httpErr.Header
+		recorded.Body = string(httpErr.Body)
+
+		return recorded
+	}
+
+	for _, sentinel := range recordedSentinels {
+		if errors.Is(err, sentinel.err) {
+			recorded.Kind = sentinel.kind
+
+			return recorded
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		recorded.Kind = ErrorKindTimeout
+	}
+
+	return recorded
+}
+
+func (re *RecordedError) error() error {
+	switch re.Kind {
+	case ErrorKindHTTP:
+		return &HTTPError{
+			StatusCode: re.StatusCode,
+			Status:     re.Status,
+			Header:     re.Header,
+			Body:       []byte(re.Body),
+		}
+	case ErrorKindAmbiguousOutcome:
+		ambiguousErr := &AmbiguousOutcomeError{Action: re.Action, Attempts: re.Attempts}
+		if re.SentAt != nil {
+			ambiguousErr.SentAt = *re.SentAt
+		}
+		if re.Cause != nil {
+			ambiguousErr.Err = re.Cause.error()
+		}
+
+		return ambiguousErr
+	case ErrorKindTimeout:
+		return &replayedError{message: re.Message, timeout: true}
+	}
+
+	for _, sentinel := range recordedSentinels {
+		if re.Kind == sentinel.kind {
+			return &replayedError{message: re.Message, err: sentinel.err}
+		}
+	}
+
+	return errors.New(re.Message)
+}
+
+// replayedError is a recorded error with its original message, matching the sentinel of its kind.
+type replayedError struct {
+	message string
+	err     error
+	timeout bool
+}
+
+func (e *replayedError) Error() string {
+	return e.message
+}
+
+func (e *replayedError) Unwrap() error {
+	return e.err
+}
+
+// Timeout and Temporary make replayed timeouts a net.Error like the recorded ones.
+func (e *replayedError) Timeout() bool {
+	return e.timeout
+}
+
+func (e *replayedError) Temporary() bool {
+	return e.timeout
+}
+
+func newRecordedRequest(request Request) (RecordedRequest, error) {
+	params := request.GetRequestParams()
+	for _, param := range volatileParams {
+		params.Del(param)
+	}
+
+	recorded := RecordedRequest{
+		Action: params.Get(fieldAction),
+		Method: request.GetMethod(),
+		Params: params,
+	}
+
+	if request.GetMethod() == MethodPOST {
+		body, err := request.GeneratePostXml()
+		if err != nil {
+			return RecordedRequest{}, err
+		}
+
+		recorded.Body = string(body)
+	}
+
+	return recorded, nil
+}
+
+func (rr RecordedRequest) matches(other RecordedRequest) bool {
+	return rr.Action == other.Action &&
+		rr.Method == other.Method &&
+		rr.Body == other.Body &&
+		reflect.DeepEqual(rr.Params, other.Params)
+}
+
+func (rr RecordedRequest) String() string {
+	return fmt.Sprintf("%s %s", rr.Method, rr.Params.Encode())
+}
+
+func newRecordedResponse(resp Response, err error) RecordedResponse {
+	if err != nil {
+		return RecordedResponse{Error: newRecordedError(err)}
+	}
+
+	if resp == nil {
+		return RecordedResponse{}
+	}
+
+	return RecordedResponse{
+		IsError: resp.IsError(),
+		Head:    rawJson(resp.GetHead()),
+		Body:    rawJson(resp.GetBody()),
+	}
+}
+
+func rawJson(data []byte) json.RawMessage {
+	if len(data) == 0 || !json.Valid(data) {
+		return nil
+	}
+
+	return json.RawMessage(data)
+}
+
+func (rr RecordedResponse) response() (Response, error) {
+	if rr.Error != nil {
+		return nil, rr.Error.error()
+	}
+
+	head, body := compactJson(rr.Head), compactJson(rr.Body)
+
+	if rr.IsError {
+		response := ErrorResponse{Head: head, Body: body}
+		if err := json.Unmarshal(rr.Head, &response.HeadObject); err != nil {
+			return nil, err
+		}
+
+		return response, nil
+	}
+
+	response := SuccessResponse{Head: head, Body: body}
+	if len(rr.Head) > 0 {
+		if err := json.Unmarshal(rr.Head, &response.HeadObject); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// compactJson undoes the indentation the cassette file adds to recorded JSON.
+func compactJson(data json.RawMessage) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+
+	return buf.Bytes()
+}
+
+// LoadCassette reads recorded interactions from the file at path.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cassette := &Cassette{}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("can not read cassette %s: %w", path, err)
+	}
+
+	return cassette, nil
+}
+
+// Save writes the interactions to the file at path.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Recorder is a Client that passes calls on to another Client and records them, to be saved as cassette.
+type Recorder struct {
+	client Client
+	path   string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder records the calls made through c. Save writes them to the file at path.
+func NewRecorder(c Client, path string) *Recorder {
+	return &Recorder{client: c, path: path}
+}
+
+func (r *Recorder) GetLogger() *log.Logger {
+	return r.client.GetLogger()
+}
+
+func (r *Recorder) Call(request Request) (Response, error) {
+	return r.CallContext(context.Background(), request)
+}
+
+func (r *Recorder) CallContext(ctx context.Context, request Request) (Response, error) {
+	recordedRequest, err := newRecordedRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := CallContext(ctx, r.client, request)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  recordedRequest,
+		Response: newRecordedResponse(resp, err),
+	})
+
+	return resp, err
+}
+
+// Save writes the interactions recorded so far to the cassette file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.Save(r.path)
+}
+
+// ReplayClient is a Client serving the responses of a cassette. Each interaction is used once, requests
+// are matched by action, method, params and POST body against the first unused interaction in recorded order.
+type ReplayClient struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayClient loads the cassette file at path.
+func NewReplayClient(path string) (*ReplayClient, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReplayClientFromCassette(cassette), nil
+}
+
+func NewReplayClientFromCassette(cassette *Cassette) *ReplayClient {
+	return &ReplayClient{
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}
+}
+
+func (rc *ReplayClient) GetLogger() *log.Logger {
+	return nil
+}
+
+func (rc *ReplayClient) Call(request Request) (Response, error) {
+	return rc.CallContext(context.Background(), request)
+}
+
+// CallContext returns an error wrapping ErrUnmatchedInteraction if no unused interaction matches the request.
+func (rc *ReplayClient) CallContext(ctx context.Context, request Request) (Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	recordedRequest, err := newRecordedRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for i, interaction := range rc.interactions {
+		if rc.used[i] || !interaction.Request.matches(recordedRequest) {
+			continue
+		}
+
+		rc.used[i] = true
+
+		return interaction.Response.response()
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnmatchedInteraction, recordedRequest)
+}
+
+// Unused returns the interactions that were not replayed yet.
+func (rc *ReplayClient) Unused() []Interaction {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range rc.interactions {
+		if !rc.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+
+	return unused
+}
+
+// Verify returns an error wrapping ErrUnusedInteractions listing the interactions that were not replayed.
+func (rc *ReplayClient) Verify() error {
+	unused := rc.Unused()
+	if len(unused) == 0 {
+		return nil
+	}
+
+	requests := make([]string, len(unused))
+	for i, interaction := range unused {
+		requests[i] = interaction.Request.String()
+	}
+
+	return fmt.Errorf("%w:\n\t%s", ErrUnusedInteractions, strings.Join(requests, "\n\t"))
+}
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_Can_Record_And_Replay_Interactions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get(fieldAction) {
+		case "ProductCreate":
+			w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "feed-1", "RequestAction": "ProductCreate", "ResponseType": "", "Timestamp": "2016-06-22T04:40:14+0200"}, "Body": ""}}`))
+		case "FeedStatus":
+			w.Write([]byte(`{"ErrorResponse": {"Head": {"RequestAction": "FeedStatus", "ErrorType": "Sender", "ErrorCode": "1", "ErrorMessage": "E001: Parameter FeedID is mandatory"}, "Body": ""}}`))
+		default:
+			w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "", "RequestAction": "GetProducts", "ResponseType": "Products", "Timestamp": "2016-06-22T04:40:14+0200"}, "Body": {"Products": ""}}}`))
+		}
+	}))
+	defer server.Close()
+
+	productCreate := NewGenericRequest("ProductCreate", MethodPOST)
+	productCreate.SetPostData(struct {
+		SellerSku string
+	}{"sku-1"})
+
+	getProducts := NewGenericRequest("GetProducts", MethodGET)
+	getProducts.SetRequestParam("Limit", "10")
+
+	requests := []Request{productCreate, NewGenericRequest("FeedStatus", MethodGET), getProducts}
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cassette.json")
+	recorder := NewRecorder(createTestClient(server.URL), path)
+
+	var recorded []Response
+	for _, request := range requests {
+		response, err := recorder.Call(request)
+		if err != nil {
+			t.Fatalf("unexpected error `%v`.", err)
+		}
+
+		recorded = append(recorded, response)
+	}
+
+	if err = recorder.Save(); err != nil {
+		t.Fatalf("can not save cassette: %v", err)
+	}
+
+	replay, err := NewReplayClient(path)
+	if err != nil {
+		t.Fatalf("can not load cassette: %v", err)
+	}
+
+	for i, request := range requests {
+		response, err := replay.Call(request)
+		if err != nil {
+			t.Fatalf("unexpected error `%v`.", err)
+		}
+
+		if !reflect.DeepEqual(response.GetHeadObject(), recorded[i].GetHeadObject()) ||
+			response.IsError() != recorded[i].IsError() ||
+			string(response.GetBody()) != string(compactJson(recorded[i].GetBody())) {
+			t.Fatalf("can not replay response. expected: `%+v` - actual: `%+v`.", recorded[i], response)
+		}
+	}
+
+	if err := replay.Verify(); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if _, err := replay.Call(getProducts); !errors.Is(err, ErrUnmatchedInteraction) {
+		t.Fatalf("expected ErrUnmatchedInteraction for used interaction, actual `%v`.", err)
+	}
+}
+
+func Test_Replay_Client_Matches_Params_And_Reports_Unused_Interactions(t *testing.T) {
+	replay := NewReplayClientFromCassette(&Cassette{Interactions: []Interaction{
+		{
+			Request:  RecordedRequest{Action: "GetProducts", Method: MethodGET, Params: map[string][]string{"Action": {"GetProducts"}, "Version": {"1.0"}, "Format": {"JSON"}, "Limit": {"10"}}},
+			Response: RecordedResponse{Error: &RecordedError{Message: "connection reset"}},
+		},
+		{
+			Request:  RecordedRequest{Action: "GetBrands", Method: MethodGET, Params: map[string][]string{"Action": {"GetBrands"}, "Version": {"1.0"}, "Format": {"JSON"}}},
+			Response: RecordedResponse{Head: []byte(`{"RequestId": "", "RequestAction": "GetBrands", "ResponseType": "Brands", "Timestamp": ""}`)},
+		},
+	}})
+
+	getProducts := NewGenericRequest("GetProducts", MethodGET)
+	getProducts.SetRequestParam("Limit", "20")
+
+	if _, err := replay.Call(getProducts); !errors.Is(err, ErrUnmatchedInteraction) {
+		t.Fatalf("expected ErrUnmatchedInteraction, actual `%v`.", err)
+	}
+
+	getProducts.SetRequestParam("Limit", "10")
+
+	if _, err := replay.Call(getProducts); err == nil || err.Error() != "connection reset" {
+		t.Fatalf("expected recorded error, actual `%v`.", err)
+	}
+
+	err := replay.Verify()
+	if !errors.Is(err, ErrUnusedInteractions) {
+		t.Fatalf("expected ErrUnusedInteractions, actual `%v`.", err)
+	}
+
+	expected := "recorded interactions were not used:\n\tGET Action=GetBrands&Format=JSON&Version=1.0"
+	if err.Error() != expected {
+		t.Fatalf("expected: `%s` - actual: `%s`.", expected, err.Error())
+	}
+}
+
+func Test_Replay_Client_Returns_Errors_Of_The_Recorded_Type(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get(fieldAction) {
+		case "ProductCreate":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("<html>maintenance</html>"))
+		}
+	}))
+	defer server.Close()
+
+	productCreate := NewGenericRequest("ProductCreate", MethodPOST)
+	productCreate.SetPostData(struct {
+		SellerSku string
+	}{"sku-1"})
+
+	requests := []Request{NewGenericRequest("GetProducts", MethodGET), productCreate}
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	policy := NewExponentialBackoffRetryPolicy()
+	policy.MaxAttempts = 1
+
+	path := filepath.Join(dir, "cassette.json")
+	recorder := NewRecorder(createTestClient(server.URL, WithRetryPolicy(policy)), path)
+
+	var recorded []error
+	for _, request := range requests {
+		_, err := recorder.Call(request)
+		recorded = append(recorded, err)
+	}
+
+	if err = recorder.Save(); err != nil {
+		t.Fatalf("can not save cassette: %v", err)
+	}
+
+	replay, err := NewReplayClient(path)
+	if err != nil {
+		t.Fatalf("can not load cassette: %v", err)
+	}
+
+	_, err = replay.Call(requests[0])
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable || httpErr.Header.Get("Retry-After") != "120" ||
+		string(httpErr.Body) != "<html>maintenance</html>" || !errors.Is(err, NoHttp200ResponseError) {
+		t.Fatalf("expected HTTPError of a 503, actual `%#v`.", err)
+	}
+
+	_, err = replay.Call(requests[1])
+
+	var ambiguousErr *AmbiguousOutcomeError
+	if !errors.As(err, &ambiguousErr) || !errors.As(recorded[1], new(*AmbiguousOutcomeError)) {
+		t.Fatalf("expected AmbiguousOutcomeError, actual `%#v`.", err)
+	}
+
+	if ambiguousErr.Action != "ProductCreate" || ambiguousErr.Attempts != 1 || ambiguousErr.SentAt.IsZero() ||
+		!errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected AmbiguousOutcomeError wrapping the HTTPError of a 502, actual `%#v`.", ambiguousErr)
+	}
+
+	if err.Error() != recorded[1].Error() {
+		t.Fatalf("expected the recorded message `%s`, actual `%s`.", recorded[1], err)
+	}
+}
+
+func Test_Replay_Client_Returns_Errors_Matching_Sentinels(t *testing.T) {
+	for _, sentinel := range []error{ErrCircuitOpen, ErrResponseTooLarge, context.DeadlineExceeded} {
+		recorded := newRecordedError(fmt.Errorf("%w: group default", sentinel))
+
+		if err := recorded.error(); !errors.Is(err, sentinel) || err.Error() != sentinel.Error()+": group default" {
+			t.Fatalf("expected error matching `%v`, actual `%v`.", sentinel, err)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCircuitGroup is the group of every action that has not been assigned to a group.
+	DefaultCircuitGroup = "default"
+
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitOpenTimeout      = 30 * time.Second
+	defaultCircuitHalfOpenRequests = 1
+)
+
+// Errors
+var (
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request pass.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request with ErrCircuitOpen until the open timeout has passed.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial requests pass to probe whether Seller Center recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitSettings configure the circuit of an action group. Zero values are replaced by the defaults of
+// 5 failures, 30s and 1 trial request.
+type CircuitSettings struct {
+	// FailureThreshold is the number of consecutive failed attempts, 503s, 504s or timeouts, opening the circuit.
+	FailureThreshold int
+	// OpenTimeout is the time the circuit stays open before trial requests are let through.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of trial requests, all of which have to succeed to close the circuit again.
+	HalfOpenRequests int
+}
+
+func (s CircuitSettings) withDefaults() CircuitSettings {
+	if s.FailureThreshold < 1 {
+		s.FailureThreshold = defaultCircuitFailureThreshold
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = defaultCircuitOpenTimeout
+	}
+	if s.HalfOpenRequests < 1 {
+		s.HalfOpenRequests = defaultCircuitHalfOpenRequests
+	}
+
+	return s
+}
+
+// CircuitStateChange is passed to the callbacks registered with OnStateChange.
+type CircuitStateChange struct {
+	Group string
+	From  CircuitState
+	To    CircuitState
+	Time  time.Time
+}
+
+// CircuitBreaker stops sending requests to Seller Center during sustained outages, e.g. maintenance windows,
+// and fails them fast with ErrCircuitOpen instead. Actions share a circuit per group. It is safe for concurrent
+// use, so a single breaker can be shared by several clients.
+type CircuitBreaker struct {
+	defaultSettings CircuitSettings
+
+	mu            sync.Mutex
+	actionGroups  map[string]string
+	groupSettings map[string]CircuitSettings
+	circuits      map[string]*circuit
+	callbacks     []func(CircuitStateChange)
+	now           func() time.Time
+
+	// generations numbers the states of all circuits, so attempts of a replaced circuit never match a later one
+	generations int
+}
+
+func NewCircuitBreaker(defaultSettings CircuitSettings) *CircuitBreaker {
+	return &CircuitBreaker{
+		defaultSettings: defaultSettings.withDefaults(),
+		actionGroups:    map[string]string{},
+		groupSettings:   map[string]CircuitSettings{},
+		circuits:        map[string]*circuit{},
+		now:             time.Now,
+	}
+}
+
+// SetActionGroup moves the actions, e.g. `GetOrders` and `GetOrderItems`, to a group with its own circuit.
+func (cb *CircuitBreaker) SetActionGroup(group string, settings CircuitSettings, actions ...string) *CircuitBreaker {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.groupSettings[group] = settings.withDefaults()
+	for _, action := range actions {
+		cb.actionGroups[action] = group
+	}
+	delete(cb.circuits, group)
+
+	return cb
+}
+
+// OnStateChange registers a callback that is called after the circuit of a group changed its state.
+func (cb *CircuitBreaker) OnStateChange(fn func(CircuitStateChange)) *CircuitBreaker {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.callbacks = append(cb.callbacks, fn)
+
+	return cb
+}
+
+// State returns the current state of the circuit of the action.
+func (cb *CircuitBreaker) State(action string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	circuit := cb.circuit(cb.group(action))
+	if circuit.state == CircuitOpen && !cb.now().Before(circuit.openUntil) {
+		return CircuitHalfOpen
+	}
+
+	return circuit.state
+}
+
+// allow reserves an attempt of the action. It fails with ErrCircuitOpen while the circuit is open or all trial
+// requests of a half-open circuit are in flight. Otherwise done has to be called with the outcome of the attempt.
+func (cb *CircuitBreaker) allow(action string) (done func(outcome circuitOutcome), err error) {
+	cb.mu.Lock()
+
+	group := cb.group(action)
+	circuit := cb.circuit(group)
+	now := cb.now()
+
+	var changes []CircuitStateChange
+	if circuit.state == CircuitOpen && !now.Before(circuit.openUntil) {
+		changes = append(changes, cb.transition(group, circuit, CircuitHalfOpen, now))
+	}
+
+	switch {
+	case circuit.state == CircuitOpen:
+		err = fmt.Errorf("%w: group %s until %s", ErrCircuitOpen, group, circuit.openUntil.Format(time.RFC3339))
+	case circuit.state == CircuitHalfOpen && circuit.trials >= circuit.settings.HalfOpenRequests:
+		err = fmt.Errorf("%w: group %s is probing", ErrCircuitOpen, group)
+	case circuit.state == CircuitHalfOpen:
+		circuit.trials++
+	}
+
+	generation := circuit.generation
+	callbacks := cb.callbacks
+	cb.mu.Unlock()
+
+	notify(callbacks, changes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(outcome circuitOutcome) {
+		cb.done(group, generation, outcome)
+	}, nil
+}
+
+func (cb *CircuitBreaker) done(group string, generation int, outcome circuitOutcome) {
+	cb.mu.Lock()
+
+	circuit := cb.circuit(group)
+
+	var changes []CircuitStateChange
+	// ... outcomes of attempts started before the last state change do not count
+	if circuit.generation == generation {
+		now := cb.now()
+		failed := outcome == circuitFailure
+
+		switch {
+		case outcome == circuitIgnored:
+			if circuit.state == CircuitHalfOpen {
+				circuit.trials--
+			}
+		case failed && circuit.state == CircuitHalfOpen:
+			changes = append(changes, cb.transition(group, circuit, CircuitOpen, now))
+		case failed:
+			circuit.failures++
+			if circuit.failures >= circuit.settings.FailureThreshold {
+				changes = append(changes, cb.transition(group, circuit, CircuitOpen, now))
+			}
+		case circuit.state == CircuitHalfOpen:
+			circuit.successes++
+			if circuit.successes >= circuit.settings.HalfOpenRequests {
+				changes = append(changes, cb.transition(group, circuit, CircuitClosed, now))
+			}
+		default:
+			circuit.failures = 0
+		}
+	}
+
+	callbacks := cb.callbacks
+	cb.mu.Unlock()
+
+	notify(callbacks, changes)
+}
+
+func (cb *CircuitBreaker) group(action string) string {
+	if group, ok := cb.actionGroups[action]; ok {
+		return group
+	}
+
+	return DefaultCircuitGroup
+}
+
+func (cb *CircuitBreaker) circuit(group string) *circuit {
+	if circuit, ok := cb.circuits[group]; ok {
+		return circuit
+	}
+
+	settings, ok := cb.groupSettings[group]
+	if !ok {
+		settings = cb.defaultSettings
+	}
+
+	circuit := &circuit{settings: settings, generation: cb.nextGeneration()}
+	cb.circuits[group] = circuit
+
+	return circuit
+}
+
+func (cb *CircuitBreaker) transition(group string, c *circuit, to CircuitState, now time.Time) CircuitStateChange {
+	change := CircuitStateChange{Group: group, From: c.state, To: to, Time: now}
+
+	c.state = to
+	c.generation = cb.nextGeneration()
+	c.failures = 0
+	c.successes = 0
+	c.trials = 0
+	if to == CircuitOpen {
+		c.openUntil = now.Add(c.settings.OpenTimeout)
+	}
+
+	return change
+}
+
+func (cb *CircuitBreaker) nextGeneration() int {
+	cb.generations++
+
+	return cb.generations
+}
+
+func notify(callbacks []func(CircuitStateChange), changes []CircuitStateChange) {
+	for _, change := range changes {
+		for _, callback := range callbacks {
+			callback(change)
+		}
+	}
+}
+
+type circuit struct {
+	settings   CircuitSettings
+	state      CircuitState
+	generation int
+	failures   int
+	successes  int
+	trials     int
+	openUntil  time.Time
+}
+
+type circuitOutcome int
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	circuitIgnored
+)
+
+// circuitOutcomeOf classifies an attempt. Outages of Seller Center, a 503 or 504 response or a timeout, are
+// failures. Cancellations of the caller and other network errors are ignored, every response else is a success.
+func circuitOutcomeOf(ctx context.Context, response *http.Response, err error) circuitOutcome {
+	if ctx.Err() != nil {
+		return circuitIgnored
+	}
+
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return circuitFailure
+		}
+
+		return circuitIgnored
+	}
+
+	if response.StatusCode == http.StatusServiceUnavailable || response.StatusCode == http.StatusGatewayTimeout {
+		return circuitFailure
+	}
+
+	return circuitSuccess
+}
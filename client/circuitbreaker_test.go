@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func createCircuitBreaker(settings CircuitSettings) (*CircuitBreaker, *fakeNow, *[]CircuitStateChange) {
+	now := &fakeNow{now: time.Date(2018, 7, 6, 15, 37, 57, 0, time.UTC)}
+
+	var changes []CircuitStateChange
+	breaker := NewCircuitBreaker(settings).OnStateChange(func(change CircuitStateChange) {
+		changes = append(changes, change)
+	})
+	breaker.now = now.Now
+
+	return breaker, now, &changes
+}
+
+func observeOutcome(t *testing.T, breaker *CircuitBreaker, action string, outcome circuitOutcome) {
+	done, err := breaker.allow(action)
+	if err != nil {
+		t.Fatalf("expected attempt to be allowed, actual `%v`.", err)
+	}
+
+	done(outcome)
+}
+
+func Test_Circuit_Breaker_Opens_After_Consecutive_Failures(t *testing.T) {
+	breaker, _, changes := createCircuitBreaker(CircuitSettings{FailureThreshold: 3})
+
+	observeOutcome(t, breaker, "GetOrders", circuitFailure)
+	observeOutcome(t, breaker, "GetOrders", circuitFailure)
+	observeOutcome(t, breaker, "GetOrders", circuitSuccess)
+	observeOutcome(t, breaker, "GetOrders", circuitFailure)
+	observeOutcome(t, breaker, "GetProducts", circuitFailure)
+
+	if state := breaker.State("GetOrders"); state != CircuitClosed {
+		t.Fatalf("expected: %s - actual: %s", CircuitClosed, state)
+	}
+
+	observeOutcome(t, breaker, "GetOrders", circuitFailure)
+
+	if _, err := breaker.allow("GetProducts"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, actual `%v`.", err)
+	}
+
+	if len(*changes) != 1 || (*changes)[0].Group != DefaultCircuitGroup || (*changes)[0].To != CircuitOpen {
+		t.Fatalf("expected change to open, actual `%+v`.", *changes)
+	}
+}
+
+func Test_Circuit_Breaker_Probes_With_Half_Open_Trial_Requests(t *testing.T) {
+	breaker, now, changes := createCircuitBreaker(CircuitSettings{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenRequests: 2})
+
+	observeOutcome(t, breaker, "GetOrders", circuitFailure)
+
+	now.Add(time.Minute)
+	if state := breaker.State("GetOrders"); state != CircuitHalfOpen {
+		t.Fatalf("expected: %s - actual: %s", CircuitHalfOpen, state)
+	}
+
+	// ... a failed trial opens the circuit again
+	observeOutcome(t, breaker, "GetOrders", circuitFailure)
+	if _, err := breaker.allow("GetOrders"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, actual `%v`.", err)
+	}
+
+	now.Add(time.Minute)
+	first, err := breaker.allow("GetOrders")
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+	second, err := breaker.allow("GetOrders")
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+	if _, err := breaker.allow("GetOrders"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen while trials are in flight, actual `%v`.", err)
+	}
+
+	first(circuitSuccess)
+	second(circuitSuccess)
+
+	if state := breaker.State("GetOrders"); state != CircuitClosed {
+		t.Fatalf("expected: %s - actual: %s", CircuitClosed, state)
+	}
+
+	var transitions []string
+	for _, change := range *changes {
+		transitions = append(transitions, change.From.String()+">"+change.To.String())
+	}
+
+	expected := []string{"closed>open", "open>half-open", "half-open>open", "open>half-open", "half-open>closed"}
+	if !reflect.DeepEqual(transitions, expected) {
+		t.Fatalf("expected: %v - actual: %v", expected, transitions)
+	}
+}
+
+func Test_Circuit_Breaker_Keeps_Action_Groups_Apart(t *testing.T) {
+	breaker, _, _ := createCircuitBreaker(CircuitSettings{FailureThreshold: 1})
+	breaker.SetActionGroup("orders", CircuitSettings{FailureThreshold: 2}, "GetOrders", "GetOrderItems")
+
+	observeOutcome(t, breaker, "GetOrders", circuitFailure)
+	observeOutcome(t, breaker, "GetProducts", circuitFailure)
+
+	if state := breaker.State("GetOrderItems"); state != CircuitClosed {
+		t.Fatalf("expected orders group to stay %s, actual %s.", CircuitClosed, state)
+	}
+
+	if state := breaker.State("GetBrands"); state != CircuitOpen {
+		t.Fatalf("expected default group to be %s, actual %s.", CircuitOpen, state)
+	}
+}
+
+func Test_Client_Fails_Fast_With_Open_Circuit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := NewExponentialBackoffRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	breaker := NewCircuitBreaker(CircuitSettings{FailureThreshold: 3})
+	c := createTestClient(server.URL, WithRetryPolicy(policy), WithCircuitBreaker(breaker))
+
+	if _, err := c.Call(NewGenericRequest("GetOrders", MethodGET)); !errors.Is(err, ErrCircuitOpen) || atomic.LoadInt32(&calls) != 3 {
+		t.Fatalf("expected retries to end with ErrCircuitOpen after 3 calls, actual %d calls, err `%v`.", calls, err)
+	}
+
+	if _, err := c.Call(NewGenericRequest("GetOrders", MethodGET)); !errors.Is(err, ErrCircuitOpen) || atomic.LoadInt32(&calls) != 3 {
+		t.Fatalf("expected call to fail fast, actual %d calls, err `%v`.", calls, err)
+	}
+}
+
+func Test_Circuit_Breaker_Ignores_Attempts_Of_Replaced_Circuits(t *testing.T) {
+	breaker, _, _ := createCircuitBreaker(CircuitSettings{FailureThreshold: 1})
+
+	done, err := breaker.allow("GetOrders")
+	if err != nil {
+		t.Fatalf("expected attempt to be allowed, actual `%v`.", err)
+	}
+
+	breaker.SetActionGroup(DefaultCircuitGroup, CircuitSettings{FailureThreshold: 1})
+	done(circuitFailure)
+
+	if state := breaker.State("GetOrders"); state != CircuitClosed {
+		t.Fatalf("expected the failure of the replaced circuit not to count, actual %s.", state)
+	}
+}
+
+func Test_Client_Does_Not_Take_Rate_Limit_Tokens_With_Open_Circuit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := NewExponentialBackoffRetryPolicy()
+	policy.MaxAttempts = 1
+
+	breaker := NewCircuitBreaker(CircuitSettings{FailureThreshold: 1})
+	limiter := NewRateLimiter(RateLimit{Rate: 0.001, Burst: 2}, RateLimitFailFast)
+	c := createTestClient(server.URL, WithRetryPolicy(policy), WithCircuitBreaker(breaker), WithRateLimiter(limiter))
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Call(NewGenericRequest("GetOrders", MethodGET)); err == nil {
+			t.Fatal("expected call to fail.")
+		}
+	}
+
+	if err := limiter.Wait(context.Background(), "GetOrders"); err != nil {
+		t.Fatalf("expected calls failing fast not to take tokens, actual `%v`.", err)
+	}
+}
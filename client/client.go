@@ -3,11 +3,13 @@ package client
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/xml"
 	"errors"
+	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
-	"net/mail"
 	"net/url"
 	"time"
 )
@@ -19,8 +21,11 @@ const fieldAction = "Action"
 const fieldVersion = "Version"
 const fieldFormat = "Format"
 
-// can be JSON` or `XML` currently only ResponseBuilder for JSON implemented
-const defaultResponseFormat = "JSON"
+// response formats supported by Seller Center, see WithResponseFormat and SetResponseFormat
+const FormatJSON = "JSON"
+const FormatXML = "XML"
+
+const defaultResponseFormat = FormatJSON
 
 const MethodGET = "GET"
 const MethodPOST = "POST"
@@ -42,6 +47,26 @@ type Client interface {
 	GetLogger() *log.Logger
 }
 
+// ContextClient is implemented by clients whose calls end when the context is done, e.g. to cancel or time out
+// a call or to pass values like a CallInfo collector to interceptors.
+type ContextClient interface {
+	CallContext(ctx context.Context, request Request) (Response, error)
+}
+
+// CallContext calls c with the context, see ContextClient. Clients without support for contexts are called with
+// Call, unless the context is done already.
+func CallContext(ctx context.Context, c Client, request Request) (Response, error) {
+	if contextClient, ok := c.(ContextClient); ok {
+		return contextClient.CallContext(ctx, request)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.Call(request)
+}
+
 type Request interface {
 	GetMethod() string
 	GetRequestParams() url.Values
@@ -70,6 +95,7 @@ type genericRequest struct {
 	action   string
 	method   string
 	version  string
+	format   string
 	postData interface{}
 }
 
@@ -81,7 +107,11 @@ func (gr genericRequest) GetRequestParams() url.Values {
 	params := url.Values{}
 	params.Add(fieldAction, gr.action)
 	params.Add(fieldVersion, gr.version)
-	params.Add(fieldFormat, defaultResponseFormat)
+	if gr.format != "" {
+		params.Add(fieldFormat, gr.format)
+	} else {
+		params.Add(fieldFormat, defaultResponseFormat)
+	}
 
 	for k, v := range gr.params {
 		params.Add(k, v[0])
@@ -112,6 +142,16 @@ func (gr *genericRequest) SetVersion(v string) {
 	gr.version = v
 }
 
+// SetResponseFormat overrides the response format of the client for this request.
+func (gr *genericRequest) SetResponseFormat(format string) {
+	gr.format = format
+}
+
+// GetResponseFormat returns the format set with SetResponseFormat or an empty string.
+func (gr genericRequest) GetResponseFormat() string {
+	return gr.format
+}
+
 func (gr genericRequest) SetRequestParam(key string, value string) {
 	gr.params.Set(key, value)
 }
@@ -126,145 +166,435 @@ type clientConfig struct {
 	Key  string
 }
 
+// NewClientConfig returns ConfigErrors if the config is invalid, see clientConfig.Validate.
 func NewClientConfig(url, user, key string, l *log.Logger) (*clientConfig, error) {
-	err := validateClientConfigEmail(user, l)
-	if err != nil {
-		return nil, err
-	}
-
-	return &clientConfig{
+	config := &clientConfig{
 		Url:  url,
 		User: user,
 		Key:  key,
-	}, nil
-}
+	}
 
-func validateClientConfigEmail(user string, l *log.Logger) error {
-	_, err := mail.ParseAddress(user)
-	if err != nil {
-		l.Fatalln(err.Error())
+	if err := config.Validate(); err != nil {
+		if l != nil {
+			l.Println(err.Error())
+		}
 
-		return err
+		return nil, err
 	}
-	return nil
+
+	return config, nil
 }
 
 type client struct {
-	httpClient       http.Client
-	clientUrlBuilder ClientUrlBuilder
-	responseBuilder  ResponseBuilder
-	logger           *log.Logger
+	httpClient            *http.Client
+	transport             http.RoundTripper
+	timeout               time.Duration
+	hasTimeout            bool
+	baseUrl               string
+	authenticator         Authenticator
+	responseBuilder       ResponseBuilder
+	xmlResponseBuilder    ResponseBuilder
+	responseFormat        string
+	retryPolicy           RetryPolicy
+	rateLimiter           *RateLimiter
+	interceptors          []Interceptor
+	loggingInterceptor    Interceptor
+	hasLoggingInterceptor bool
+	handler               Handler
+	userAgent             string
+	defaultHeader         http.Header
+	logger                *log.Logger
+	log                   Logger
+	redactor              *Redactor
+	clock                 Clock
+	clockSkew             *clockSkew
+	metrics               MetricsCollector
+	metricsLabels         map[string]string
+	credentials           CredentialsProvider
+	maxResponseSize       int64
+	circuitBreaker        *CircuitBreaker
 }
 
-func NewClient(clientConfig clientConfig, l *log.Logger) Client {
-	timeout := time.Duration(int64(timeoutInSeconds) * int64(time.Second))
+func NewClient(clientConfig clientConfig, l *log.Logger, opts ...Option) Client {
+	c := &client{
+		httpClient:     newDefaultHttpClient(),
+		responseFormat: defaultResponseFormat,
+		retryPolicy:    NewExponentialBackoffRetryPolicy(),
+		logger:         l,
+		log:            NewStdLogger(l, LevelInfo),
+		redactor:       NewRedactor(DefaultRedactedFields...),
+		clock:          SystemClock,
+		clockSkew:      &clockSkew{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.transport != nil {
+		c.httpClient.Transport = c.transport
+	}
+	if c.hasTimeout {
+		c.httpClient.Timeout = c.timeout
+	}
+
+	c.responseBuilder = responseBuilder{maxSize: c.maxResponseSize}
+	c.xmlResponseBuilder = xmlResponseBuilder{jsonResponseBuilder: responseBuilder{maxSize: c.maxResponseSize}}
+
+	c.baseUrl = clientConfig.Url
+	if c.authenticator == nil {
+		c.authenticator = newDefaultAuthenticator(clientConfig, c.credentials, c.clock, c.clockSkew)
+	} else if authenticator, ok := c.authenticator.(*QuerySigningAuthenticator); ok {
+		// ... a copy, so clients sharing the authenticator compensate their own clock skew
+		withSkew := *authenticator
+		withSkew.skew = c.clockSkew
+		c.authenticator = &withSkew
+	}
 
-	return &client{
-		httpClient:       http.Client{Timeout: timeout},
-		clientUrlBuilder: NewClientUrlBuilder(clientConfig),
-		responseBuilder:  NewResponseBuilder(),
-		logger:           l,
+	if !c.hasLoggingInterceptor {
+		c.loggingInterceptor = NewLeveledLoggingInterceptor(c.log)
 	}
+
+	interceptors := make([]Interceptor, 0, len(c.interceptors)+1)
+	interceptors = append(append(interceptors, c.interceptors...), c.loggingInterceptor)
+	c.handler = chainInterceptors(c.send, interceptors...)
+
+	return c
+}
+
+func newDefaultHttpClient() *http.Client {
+	return &http.Client{Timeout: time.Duration(int64(timeoutInSeconds) * int64(time.Second))}
 }
 
 func (c client) GetLogger() *log.Logger {
 	return c.logger
 }
 
+func (c client) ClockSkew() time.Duration {
+	return c.clockSkew.get()
+}
+
 func (c client) Call(request Request) (Response, error) {
+	return c.CallContext(context.Background(), request)
+}
+
+func (c client) CallContext(ctx context.Context, request Request) (Response, error) {
+	return c.handler(ctx, request)
+}
+
+// send is the innermost Handler of the interceptor chain.
+func (c client) send(ctx context.Context, request Request) (Response, error) {
+	start := time.Now()
+	stats := &callStats{}
+
 	var resp Response
 	var err error
 	switch request.GetMethod() {
 	case MethodGET:
-		resp, err = c.Get(request)
+		resp, err = c.get(ctx, request, stats)
 	case MethodPOST:
-		resp, err = c.Post(request)
+		resp, err = c.post(ctx, request, stats)
 	default:
 		resp, err = nil, NotSupportedMethod
 	}
 
-	c.logger.Printf("Call response: %#v --- Err: %#v", resp, err)
+	duration := time.Since(start)
+	if c.metrics != nil {
+		c.metrics.ObserveCall(newCallMetrics(request, resp, err, stats, duration, c.metricsLabels))
+	}
+
+	if collector := callInfoCollectorFromContext(ctx); collector != nil {
+		collector.add(newCallInfo(request, resp, stats, duration))
+	}
 
 	return resp, err
 }
 
 func (c client) Get(request Request) (Response, error) {
-	getUrl, err := c.clientUrlBuilder.BuildUrl(request.GetRequestParams())
+	return c.GetContext(context.Background(), request)
+}
+
+func (c client) GetContext(ctx context.Context, request Request) (Response, error) {
+	return c.get(ctx, request, &callStats{})
+}
+
+func (c client) get(ctx context.Context, request Request, stats *callStats) (Response, error) {
+	params, responseBuilder := c.requestParams(ctx, request)
+
+	getUrl, err := buildRequestUrl(c.baseUrl, params)
 	if err != nil {
 		return nil, err
 	}
 
-	for i := 1; i <= maxRetries; i++ {
-		time.Sleep(time.Duration(i-1) * 200 * time.Millisecond)
+	return c.do(ctx, MethodGET, params.Get(fieldAction), getUrl, nil, responseBuilder, stats)
+}
 
-		response, err := c.httpClient.Get(getUrl)
+func (c client) Post(request Request) (Response, error) {
+	return c.PostContext(context.Background(), request)
+}
 
-		if response == nil || response.StatusCode == 503 {
-			c.logger.Printf("Sellercenter Client Get call. empty response or http 503, url: %s, try: %d \n", getUrl, i)
-		} else {
-			c.logger.Printf("Sellercenter Client Get call. httpResponseCode: %d, url: %s, try: %d \n", response.StatusCode, getUrl, i)
+func (c client) PostContext(ctx context.Context, request Request) (Response, error) {
+	return c.post(ctx, request, &callStats{})
+}
 
-			if err != nil {
-				return nil, err
-			}
+func (c client) post(ctx context.Context, request Request, stats *callStats) (Response, error) {
+	params, responseBuilder := c.requestParams(ctx, request)
 
-			return c.responseBuilder.BuildResponse(*response)
-		}
+	postUrl, err := buildRequestUrl(c.baseUrl, params)
+	if err != nil {
+		return nil, err
 	}
 
-	if err == nil {
-		err = errors.New("empty response")
+	postDataXml, err := request.GeneratePostXml()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, err
+	return c.do(ctx, MethodPOST, params.Get(fieldAction), postUrl, postDataXml, responseBuilder, stats)
 }
 
-func (c client) Post(request Request) (Response, error) {
-	postUrl, err := c.clientUrlBuilder.BuildUrl(request.GetRequestParams())
-	if err != nil {
-		return nil, err
+// requestParams returns the params of the request in the response format of the request or of the client,
+// together with the ResponseBuilder for that format. Calls made by StreamContext get a streaming builder.
+func (c client) requestParams(ctx context.Context, request Request) (url.Values, ResponseBuilder) {
+	format := c.responseFormat
+	if r, ok := request.(interface{ GetResponseFormat() string }); ok && r.GetResponseFormat() != "" {
+		format = r.GetResponseFormat()
 	}
 
-	postDataXml, err := request.GeneratePostXml()
-	if err != nil {
-		return nil, err
+	params := request.GetRequestParams()
+	params.Set(fieldFormat, format)
+
+	if stream := streamFromContext(ctx); stream != nil {
+		return params, streamResponseBuilder{stream: stream, maxSize: c.maxResponseSize, xml: format == FormatXML}
+	}
+
+	if format == FormatXML {
+		return params, c.xmlResponseBuilder
 	}
 
-	for i := 1; i <= maxRetries; i++ {
-		time.Sleep(time.Duration(i-1) * 200 * time.Millisecond)
+	return params, c.responseBuilder
+}
 
+// do sends the request and repeats it for as long as the retry policy asks for it.
+func (c client) do(ctx context.Context, method string, action string, requestUrl string, postDataXml []byte, responseBuilder ResponseBuilder, stats *callStats) (Response, error) {
+	var postData []byte
+	if method == MethodPOST {
 		var buf bytes.Buffer
 		g := gzip.NewWriter(&buf)
-		if _, err = g.Write(postDataXml); err != nil {
-			c.logger.Printf("Sellercenter Client Post call. Error in gzip compression (%s), url: %s, data: %s, try: %d \n", err, postUrl, string(postDataXml), i)
+		if _, err := g.Write(postDataXml); err != nil {
+			c.log.Error("Sellercenter Client Post call. Error in gzip compression", "action", action, "error", err)
 			return nil, err
 		}
-		if err = g.Close(); err != nil {
-			c.logger.Printf("Sellercenter Client Post call. Error in gzip compression (%s), url: %s, data: %s, try: %d \n", err, postUrl, string(postDataXml), i)
+		if err := g.Close(); err != nil {
+			c.log.Error("Sellercenter Client Post call. Error in gzip compression", "action", action, "error", err)
 			return nil, err
 		}
 
-		request, err := http.NewRequest("POST", postUrl, &buf)
-		request.Header.Set("Content-Type", "text/html")
-		request.Header.Set("Content-Encoding", "gzip")
-		response, err := c.httpClient.Do(request)
+		postData = buf.Bytes()
+	}
 
-		if response == nil || response.StatusCode == 503 {
-			c.logger.Printf("Sellercenter Client Post call. empty response or http 503, url: %s, data: %s, try: %d \n", postUrl, string(postDataXml), i)
+	redactedUrl := c.redactor.RedactUrl(requestUrl)
+	if method == MethodPOST {
+		c.log.Debug("Sellercenter Client Post data", "action", action, "data", c.redactor.RedactPayload(postDataXml))
+	}
+
+	var firstSentAt time.Time
+	for i := 1; ; i++ {
+		// ... calls failing fast on an open circuit do not take a token of the rate limiter
+		circuitDone := func(circuitOutcome) {}
+		if c.circuitBreaker != nil {
+			var err error
+			if circuitDone, err = c.circuitBreaker.allow(action); err != nil {
+				return nil, err
+			}
+		}
+
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(ctx, action); err != nil {
+				circuitDone(circuitIgnored)
+				return nil, err
+			}
+		}
+
+		// ... requests of a done context are not sent, so their outcome is not ambiguous
+		if err := ctx.Err(); err != nil {
+			circuitDone(circuitIgnored)
+			return nil, err
+		}
+
+		httpRequest, err := c.newHttpRequest(ctx, method, requestUrl, postData)
+		if err != nil {
+			circuitDone(circuitIgnored)
+			return nil, err
+		}
+
+		sentAt := c.clock.Now()
+		if i == 1 {
+			firstSentAt = sentAt
+		}
+
+		response, err := c.httpClient.Do(httpRequest)
+		if err != nil {
+			// ... the error holds the signed url, which must neither be logged nor returned
+			err = c.redactor.redactUrlError(err)
+		}
+		receivedAt := c.clock.Now()
+		stats.observeAttempt(response)
+		circuitDone(circuitOutcomeOf(ctx, response, err))
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if response != nil {
+				if resp, err := responseBuilder.BuildResponse(*response); err == nil {
+					return resp, nil
+				}
+			}
+
+			if isAmbiguousOutcome(method, response, err) {
+				return nil, newAmbiguousOutcomeError(action, firstSentAt, i, ctxErr)
+			}
+
+			return nil, ctxErr
+		}
+
+		delay, retry := c.retryPolicy.ShouldRetry(RetryAttempt{
+			Number:   i,
+			Action:   action,
+			Method:   method,
+			Response: response,
+			Err:      err,
+		})
+
+		keyvals := []interface{}{"action", action, "method", method, "url", redactedUrl, "try", i}
+		if response == nil {
+			keyvals = append(keyvals, "error", err)
+		} else {
+			keyvals = append(keyvals, "httpResponseCode", response.StatusCode)
+		}
+
+		if retry {
+			c.log.Warn("Sellercenter Client call failed, retrying", append(keyvals, "delay", delay)...)
 		} else {
-			c.logger.Printf("Sellercenter Client Post call. httpResponseCode: %d, url: %s, data: %s, try: %d \n", response.StatusCode, postUrl, string(postDataXml), i)
+			c.log.Debug("Sellercenter Client call", keyvals...)
+		}
 
+		if !retry {
 			if err != nil {
+				if isAmbiguousOutcome(method, nil, err) {
+					return nil, newAmbiguousOutcomeError(action, firstSentAt, i, err)
+				}
+
 				return nil, err
 			}
 
-			return c.responseBuilder.BuildResponse(*response)
+			resp, err := responseBuilder.BuildResponse(*response)
+			if err == nil {
+				serverTime, _ := ResponseTimestamp(resp)
+				c.clockSkew.observe(serverTime, sentAt, receivedAt)
+			}
+
+			if err != nil && isAmbiguousOutcome(method, response, err) {
+				return nil, newAmbiguousOutcomeError(action, firstSentAt, i, err)
+			}
+
+			return resp, err
+		}
+
+		c.observeDiscardedResponse(response, sentAt, receivedAt)
+		closeResponse(response)
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
 		}
 	}
+}
 
-	if err == nil {
-		err = errors.New("empty response")
+func (c client) newHttpRequest(ctx context.Context, method string, requestUrl string, postData []byte) (*http.Request, error) {
+	var body io.Reader
+	if method == MethodPOST {
+		body = bytes.NewReader(postData)
 	}
 
-	return nil, err
+	request, err := http.NewRequestWithContext(ctx, method, requestUrl, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range c.defaultHeader {
+		request.Header[key] = append([]string(nil), values...)
+	}
+
+	for key, values := range headerFromContext(ctx) {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	if c.userAgent != "" {
+		request.Header.Set("User-Agent", c.userAgent)
+	}
+
+	if method == MethodPOST {
+		request.Header.Set("Content-Type", "text/html")
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+
+	if err := c.authenticator.Authenticate(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// observeDiscardedResponse learns the clock skew from the ErrorResponse of a response that is retried instead
+// of being built, e.g. a 503 during maintenance. The body is read up to the maximum response size.
+func (c client) observeDiscardedResponse(response *http.Response, sentAt time.Time, receivedAt time.Time) {
+	if response == nil || response.Body == nil {
+		return
+	}
+
+	reader, err := limitResponseBody(*response, c.maxResponseSize)
+	if err != nil {
+		return
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return
+	}
+
+	if converted, err := xmlToJson(body); err == nil {
+		body = converted
+	}
+
+	if resp, err := (responseBuilder{}).handleErrorResponse(body); err == nil {
+		serverTime, _ := ResponseTimestamp(resp)
+		c.clockSkew.observe(serverTime, sentAt, receivedAt)
+	}
+}
+
+// closeResponse drains and closes the body of a response that is going to be discarded,
+// so that the underlying connection can be reused.
+func closeResponse(response *http.Response) {
+	if response == nil || response.Body == nil {
+		return
+	}
+
+	io.Copy(ioutil.Discard, io.LimitReader(response.Body, 4096))
+	response.Body.Close()
+}
+
+// sleepContext pauses for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
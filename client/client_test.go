@@ -1,8 +1,15 @@
 package client
 
 import (
+	"context"
 	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func Test_Can_Get_Request_Params_From_Generic_Request(t *testing.T) {
@@ -106,3 +113,51 @@ func Test_Can_Generate_Post_Xml_Generic_Request(t *testing.T) {
 		t.Fatal("can not get post xml, expected err to be nil.")
 	}
 }
+
+func createTestClient(apiUrl string, opts ...Option) Client {
+	logger := log.New(ioutil.Discard, "", 0)
+
+	return NewClient(clientConfig{Url: apiUrl, User: "abc@sellercenter.net", Key: "1234567890"}, logger, opts...)
+}
+
+func Test_Call_Context_Stops_Retrying_When_Context_Is_Done(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	response, err := CallContext(ctx, createTestClient(server.URL), NewGenericRequest("GetProducts", MethodGET))
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, actual `%v`.", err)
+	}
+
+	if response != nil {
+		t.Fatalf("expected response to be nil, actual `%v`.", response)
+	}
+
+	if n := atomic.LoadInt32(&calls); n >= maxRetries {
+		t.Fatalf("expected retry loop to stop early, but server was called %d times.", n)
+	}
+}
+
+func Test_Call_Context_Returns_Error_For_Canceled_Context(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected no request to be sent.")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := CallContext(ctx, createTestClient(server.URL), NewGenericRequest("ProductCreate", MethodPOST))
+
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, actual `%v`.", err)
+	}
+}
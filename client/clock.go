@@ -0,0 +1,86 @@
+package client
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ResponseTimestampFormat is the format of the Timestamp in the Head of responses, with the offset of the venue.
+const ResponseTimestampFormat = "2006-01-02T15:04:05-0700"
+
+const minClockSkew = time.Second
+
+// Clock provides the time used to sign requests.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the default Clock based on time.Now.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// clockSkew is the estimated offset of the Seller Center clock to the local clock.
+type clockSkew struct {
+	nanos int64
+}
+
+func (cs *clockSkew) get() time.Duration {
+	if cs == nil {
+		return 0
+	}
+
+	return time.Duration(atomic.LoadInt64(&cs.nanos))
+}
+
+// observe updates the estimate from a server timestamp of a response to a request sent at sentAt and
+// received at receivedAt. Server timestamps only have second precision, so smaller offsets are ignored.
+func (cs *clockSkew) observe(serverTime time.Time, sentAt time.Time, receivedAt time.Time) {
+	if cs == nil || serverTime.IsZero() {
+		return
+	}
+
+	localTime := sentAt.Add(receivedAt.Sub(sentAt) / 2)
+
+	skew := serverTime.Sub(localTime)
+	if skew > -minClockSkew && skew < minClockSkew {
+		skew = 0
+	}
+
+	atomic.StoreInt64(&cs.nanos, int64(skew))
+}
+
+// ClockSkew returns how far the Seller Center clock is estimated to be ahead of the local clock. The client
+// adds this offset to the Timestamp of signed requests. ok is false if the client does not measure skew.
+func ClockSkew(c Client) (skew time.Duration, ok bool) {
+	reporter, ok := c.(interface{ ClockSkew() time.Duration })
+	if !ok {
+		return 0, false
+	}
+
+	return reporter.ClockSkew(), true
+}
+
+// ResponseTimestamp returns the Timestamp in the Head of the response, in the zone of the venue. ok is false if
+// the response has none.
+func ResponseTimestamp(response Response) (timestamp time.Time, ok bool) {
+	var raw string
+	switch head := response.GetHeadObject().(type) {
+	case headSuccessResponse:
+		raw = head.Timestamp
+	case HeadErrorResponse:
+		raw = head.Timestamp
+	}
+
+	for _, layout := range []string{ResponseTimestampFormat, time.RFC3339} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_Clock_Skew_Ignores_Sub_Second_Offsets(t *testing.T) {
+	skew := &clockSkew{}
+	now := time.Date(2018, 7, 6, 15, 37, 57, 0, time.UTC)
+
+	skew.observe(now.Add(500*time.Millisecond), now, now.Add(100*time.Millisecond))
+	if skew.get() != 0 {
+		t.Fatalf("expected no skew, actual %s.", skew.get())
+	}
+
+	skew.observe(now.Add(-5*time.Minute), now, now.Add(2*time.Second))
+	if expected := -5*time.Minute - time.Second; skew.get() != expected {
+		t.Fatalf("expected skew %s, actual %s.", expected, skew.get())
+	}
+}
+
+func Test_Client_Signs_With_Current_Time_And_Compensates_Clock_Skew(t *testing.T) {
+	localNow := time.Date(2018, 7, 6, 13, 37, 57, 0, time.UTC)
+	serverNow := localNow.Add(time.Hour)
+
+	timestamps := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamps = append(timestamps, r.URL.Query().Get("Timestamp"))
+		fmt.Fprintf(w, `{"SuccessResponse": {"Head": {"RequestId": "1", "Timestamp": "%s"}, "Body": ""}}`, serverNow.In(time.FixedZone("", 7200)).Format(ResponseTimestampFormat))
+	}))
+	defer server.Close()
+
+	clock := &fakeNow{now: localNow}
+	c := createTestClient(server.URL, WithClock(clock))
+
+	if _, err := c.Call(NewGenericRequest("GetOrders", MethodGET)); err != nil {
+		t.Fatalf("expected error to be nil, actual `%s`.", err)
+	}
+
+	if skew, ok := ClockSkew(c); !ok || skew != time.Hour {
+		t.Fatalf("expected clock skew of 1h, actual %s.", skew)
+	}
+
+	clock.Add(time.Minute)
+
+	if _, err := c.Call(NewGenericRequest("GetOrders", MethodGET)); err != nil {
+		t.Fatalf("expected error to be nil, actual `%s`.", err)
+	}
+
+	expected := []string{"2018-07-06T13:37:57Z", "2018-07-06T14:38:57Z"}
+	if len(timestamps) != 2 || timestamps[0] != expected[0] || timestamps[1] != expected[1] {
+		t.Fatalf("unexpected signed timestamps. expected: `%v` - actual: `%v`.", expected, timestamps)
+	}
+}
+
+func Test_Clock_Skew_Is_Not_Available_For_Fake_Client(t *testing.T) {
+	if _, ok := ClockSkew(FakeClient{}); ok {
+		t.Fatal("expected clock skew not to be available.")
+	}
+}
+
+func Test_Client_Learns_Clock_Skew_From_Error_Responses(t *testing.T) {
+	localNow := time.Date(2018, 7, 6, 13, 37, 57, 0, time.UTC)
+
+	responses := []struct {
+		status int
+		body   string
+	}{
+		// ... a retried 503 during maintenance
+		{http.StatusServiceUnavailable, `{"ErrorResponse": {"Head": {"RequestAction": "GetOrders", "ErrorType": "Platform", "ErrorCode": "E1000", "ErrorMessage": "", "Timestamp": "2018-07-06T13:27:57+0000"}, "Body": ""}}`},
+		{http.StatusOK, `{"SuccessResponse": {"Head": {"RequestId": "1", "Timestamp": ""}, "Body": ""}}`},
+		{http.StatusBadRequest, `{"ErrorResponse": {"Head": {"RequestAction": "GetOrders", "ErrorType": "Sender", "ErrorCode": "E003", "ErrorMessage": "Timestamp has expired", "Timestamp": "2018-07-06T14:37:57+0000"}, "Body": ""}}`},
+	}
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := responses[calls]
+		calls++
+
+		w.WriteHeader(response.status)
+		w.Write([]byte(response.body))
+	}))
+	defer server.Close()
+
+	policy := NewExponentialBackoffRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	c := createTestClient(server.URL, WithClock(&fakeNow{now: localNow}), WithRetryPolicy(policy))
+
+	if _, err := c.Call(NewGenericRequest("GetOrders", MethodGET)); err != nil {
+		t.Fatalf("expected error to be nil, actual `%s`.", err)
+	}
+
+	if skew, _ := ClockSkew(c); skew != -10*time.Minute {
+		t.Fatalf("expected clock skew of -10m from the retried response, actual %s.", skew)
+	}
+
+	response, err := c.Call(NewGenericRequest("GetOrders", MethodGET))
+	if err != nil || !response.IsError() {
+		t.Fatalf("expected an ErrorResponse, actual `%v`, `%v`.", response, err)
+	}
+
+	if skew, _ := ClockSkew(c); skew != time.Hour {
+		t.Fatalf("expected clock skew of 1h from the E003 response, actual %s.", skew)
+	}
+}
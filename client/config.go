@@ -0,0 +1,286 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables read by NewClientConfigFromEnv and LoadClientConfig.
+const (
+	EnvUrl        = "SELLERCENTER_URL"
+	EnvUser       = "SELLERCENTER_USER"
+	EnvKey        = "SELLERCENTER_KEY"
+	EnvConfigFile = "SELLERCENTER_CONFIG_FILE"
+	EnvProfile    = "SELLERCENTER_PROFILE"
+)
+
+// DefaultProfile is the profile read if none is given.
+const DefaultProfile = "default"
+
+// Errors
+var (
+	ErrInvalidConfig   = errors.New("invalid client config")
+	ErrProfileNotFound = errors.New("config profile not found")
+)
+
+// ConfigError is an invalid field of a client config. Value is left empty for the Key.
+type ConfigError struct {
+	Field  string
+	Value  string
+	Reason string
+	Err    error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+	}
+
+	return fmt.Sprintf("%s `%s`: %s", e.Field, e.Value, e.Reason)
+}
+
+func (e *ConfigError) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors lists all invalid fields of a client config. errors.Is matches it with ErrInvalidConfig.
+type ConfigErrors []*ConfigError
+
+func (ce ConfigErrors) Error() string {
+	messages := make([]string, len(ce))
+	for i, err := range ce {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidConfig, strings.Join(messages, "; "))
+}
+
+func (ce ConfigErrors) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+// Validate checks that Url is an absolute http or https URL, User an e-mail address and Key set.
+func (config clientConfig) Validate() error {
+	var errs ConfigErrors
+
+	if config.Url == "" {
+		errs = append(errs, &ConfigError{Field: "Url", Reason: "is empty"})
+	} else if u, err := url.ParseRequestURI(config.Url); err != nil {
+		errs = append(errs, &ConfigError{Field: "Url", Value: config.Url, Reason: "is not a valid URL", Err: err})
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		errs = append(errs, &ConfigError{Field: "Url", Value: config.Url, Reason: "scheme must be http or https"})
+	} else if u.Host == "" {
+		errs = append(errs, &ConfigError{Field: "Url", Value: config.Url, Reason: "has no host"})
+	}
+
+	if config.User == "" {
+		errs = append(errs, &ConfigError{Field: "User", Reason: "is empty"})
+	} else if address, err := mail.ParseAddress(config.User); err != nil {
+		errs = append(errs, &ConfigError{Field: "User", Value: config.User, Reason: "is not an e-mail address", Err: err})
+	} else if address.Address != config.User {
+		errs = append(errs, &ConfigError{Field: "User", Value: config.User, Reason: "must be a bare e-mail address"})
+	}
+
+	if strings.TrimSpace(config.Key) == "" {
+		errs = append(errs, &ConfigError{Field: "Key", Reason: "is empty"})
+	} else if strings.ContainsAny(config.Key, " \t\r\n") {
+		errs = append(errs, &ConfigError{Field: "Key", Reason: "contains whitespace"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// NewClientConfigFromEnv reads the config from SELLERCENTER_URL, SELLERCENTER_USER and SELLERCENTER_KEY.
+func NewClientConfigFromEnv() (*clientConfig, error) {
+	config := &clientConfig{}
+	config.overrideFromEnv()
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// LoadClientConfig reads the profile SELLERCENTER_PROFILE, or the default one, from the file
+// SELLERCENTER_CONFIG_FILE, or ~/.sellercenter/config if it exists. SELLERCENTER_URL, SELLERCENTER_USER and
+// SELLERCENTER_KEY override the fields of the profile, without file the config is read from them alone.
+func LoadClientConfig() (*clientConfig, error) {
+	path := os.Getenv(EnvConfigFile)
+	if path == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, ".sellercenter", "config")
+			if _, err := os.Stat(path); err != nil {
+				path = ""
+			}
+		}
+	}
+
+	config := &clientConfig{}
+	if path != "" {
+		profile, err := readProfile(path, os.Getenv(EnvProfile))
+		if err != nil {
+			return nil, err
+		}
+
+		config = profile
+	}
+
+	config.overrideFromEnv()
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// LoadClientConfigProfile reads a named profile, the default one if empty, from a profile file. The format
+// follows the extension: .json holds an object of profiles, .yaml and .yml a map of profiles with url,
+// user and key, any other extension is INI-style with a [profile] section per profile.
+func LoadClientConfigProfile(path string, profile string) (*clientConfig, error) {
+	config, err := readProfile(path, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (config *clientConfig) overrideFromEnv() {
+	for env, field := range map[string]*string{EnvUrl: &config.Url, EnvUser: &config.User, EnvKey: &config.Key} {
+		if value, ok := os.LookupEnv(env); ok {
+			*field = value
+		}
+	}
+}
+
+type profileFields struct {
+	Url  string `json:"url"`
+	User string `json:"user"`
+	Key  string `json:"key"`
+}
+
+func readProfile(path string, profile string) (*clientConfig, error) {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles map[string]profileFields
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &profiles)
+	case ".yaml", ".yml":
+		profiles, err = parseSections(string(data), yamlSection)
+	default:
+		profiles, err = parseSections(string(data), iniSection)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can not read config file %s: %w", path, err)
+	}
+
+	fields, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s in %s", ErrProfileNotFound, profile, path)
+	}
+
+	return &clientConfig{Url: fields.Url, User: fields.User, Key: fields.Key}, nil
+}
+
+// sectionFunc returns the name of the section a line starts, false if it does not start one.
+type sectionFunc func(line string, rawLine string) (string, bool)
+
+func iniSection(line string, _ string) (string, bool) {
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+		return strings.TrimSpace(line[1 : len(line)-1]), true
+	}
+
+	return "", false
+}
+
+func yamlSection(line string, rawLine string) (string, bool) {
+	if rawLine[0] != ' ' && rawLine[0] != '\t' && strings.HasSuffix(line, ":") {
+		return unquote(strings.TrimSpace(strings.TrimSuffix(line, ":"))), true
+	}
+
+	return "", false
+}
+
+// parseSections reads the flat `key = value` or `key: value` lines of named sections, the subset of INI and
+// YAML profile files use.
+func parseSections(data string, section sectionFunc) (map[string]profileFields, error) {
+	profiles := map[string]profileFields{}
+
+	current := ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		rawLine := scanner.Text()
+		line := strings.TrimSpace(rawLine)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if name, ok := section(line, rawLine); ok {
+			current = name
+			if _, ok := profiles[current]; !ok {
+				profiles[current] = profileFields{}
+			}
+			continue
+		}
+
+		separator := strings.IndexAny(line, "=:")
+		if separator < 0 || current == "" {
+			return nil, fmt.Errorf("line %d: expected `key = value` in a profile", lineNumber)
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[:separator]))
+		value := unquote(strings.TrimSpace(line[separator+1:]))
+
+		fields := profiles[current]
+		switch key {
+		case "url":
+			fields.Url = value
+		case "user":
+			fields.User = value
+		case "key":
+			fields.Key = value
+		}
+		profiles[current] = fields
+	}
+
+	return profiles, scanner.Err()
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_Client_Config_Validation_Returns_All_Invalid_Fields(t *testing.T) {
+	tests := []struct {
+		url    string
+		user   string
+		key    string
+		fields []string
+	}{
+		{"https://sellercenter-api.example.com/", "user@sellercenter.net", "1234567890", nil},
+		{"", "", "", []string{"Url", "User", "Key"}},
+		{"ftp://sellercenter-api.example.com/", "user@sellercenter.net", "1234567890", []string{"Url"}},
+		{"sellercenter-api.example.com", "user@sellercenter.net", "1234567890", []string{"Url"}},
+		{"https://sellercenter-api.example.com/", "not an e-mail", "1234567890", []string{"User"}},
+		{"https://sellercenter-api.example.com/", "User <user@sellercenter.net>", "1234567890", []string{"User"}},
+		{"https://sellercenter-api.example.com/", "user@sellercenter.net", "12345 67890", []string{"Key"}},
+	}
+
+	for _, test := range tests {
+		config, err := NewClientConfig(test.url, test.user, test.key, nil)
+
+		if test.fields == nil {
+			if err != nil || config == nil {
+				t.Fatalf("expected valid config for `%s`, actual `%v`.", test.url, err)
+			}
+			continue
+		}
+
+		var configErrors ConfigErrors
+		if !errors.As(err, &configErrors) || !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("expected ConfigErrors, actual `%v`.", err)
+		}
+
+		var fields []string
+		for _, configError := range configErrors {
+			fields = append(fields, configError.Field)
+		}
+
+		if !reflect.DeepEqual(fields, test.fields) {
+			t.Fatalf("expected invalid fields `%v`, actual `%v`.", test.fields, fields)
+		}
+	}
+}
+
+func setEnv(t *testing.T, env map[string]string) func() {
+	previous := map[string]*string{}
+	for key, value := range env {
+		if old, ok := os.LookupEnv(key); ok {
+			previous[key] = &old
+		} else {
+			previous[key] = nil
+		}
+
+		if err := os.Setenv(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return func() {
+		for key, value := range previous {
+			if value == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *value)
+			}
+		}
+	}
+}
+
+func Test_Can_Read_Client_Config_From_Env(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		EnvUrl:  "https://sellercenter-api.example.com/",
+		EnvUser: "user@sellercenter.net",
+		EnvKey:  "1234567890",
+	})()
+
+	config, err := NewClientConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	expected := &clientConfig{Url: "https://sellercenter-api.example.com/", User: "user@sellercenter.net", Key: "1234567890"}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("expected `%+v`, actual `%+v`.", expected, config)
+	}
+}
+
+func Test_Can_Read_Client_Config_Profiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"config.json": `{
+  "default": {"url": "https://sellercenter-api.example.com/", "user": "user@sellercenter.net", "key": "1234567890"},
+  "staging": {"url": "https://staging.example.com/", "user": "staging@sellercenter.net", "key": "abcdef"}
+}`,
+		"config.yaml": `# Seller Center accounts
+default:
+  url: https://sellercenter-api.example.com/
+  user: user@sellercenter.net
+  key: "1234567890"
+staging:
+  url: https://staging.example.com/
+  user: staging@sellercenter.net
+  key: abcdef
+`,
+		"config": `; Seller Center accounts
+[default]
+url = https://sellercenter-api.example.com/
+user = user@sellercenter.net
+key = 1234567890
+
+[staging]
+url = https://staging.example.com/
+user = staging@sellercenter.net
+key = abcdef
+`,
+	}
+
+	expected := &clientConfig{Url: "https://staging.example.com/", User: "staging@sellercenter.net", Key: "abcdef"}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := LoadClientConfigProfile(path, "staging")
+		if err != nil {
+			t.Fatalf("%s: unexpected error `%v`.", name, err)
+		}
+
+		if !reflect.DeepEqual(config, expected) {
+			t.Fatalf("%s: expected `%+v`, actual `%+v`.", name, expected, config)
+		}
+
+		if config, err = LoadClientConfigProfile(path, ""); err != nil || config.Key != "1234567890" {
+			t.Fatalf("%s: expected default profile, actual `%+v` `%v`.", name, config, err)
+		}
+
+		if _, err = LoadClientConfigProfile(path, "production"); !errors.Is(err, ErrProfileNotFound) {
+			t.Fatalf("%s: expected ErrProfileNotFound, actual `%v`.", name, err)
+		}
+	}
+
+	defer setEnv(t, map[string]string{
+		EnvConfigFile: filepath.Join(dir, "config"),
+		EnvProfile:    "staging",
+		EnvKey:        "from-env",
+	})()
+
+	config, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if config.Url != expected.Url || config.Key != "from-env" {
+		t.Fatalf("expected staging profile with key from env, actual `%+v`.", config)
+	}
+}
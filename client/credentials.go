@@ -0,0 +1,279 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors
+var (
+	ErrNoCredentials = errors.New("no credentials")
+)
+
+// Credentials are the user and API key requests are signed with. A zero Expires never expires.
+type Credentials struct {
+	User    string
+	Key     string
+	Expires time.Time
+}
+
+func (c Credentials) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !now.Before(c.Expires)
+}
+
+// CredentialsProvider is queried by the client for the credentials of every request, see WithCredentialsProvider.
+type CredentialsProvider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+type staticCredentialsProvider Credentials
+
+// NewStaticCredentialsProvider always provides the same credentials.
+func NewStaticCredentialsProvider(user string, key string) CredentialsProvider {
+	return staticCredentialsProvider{User: user, Key: key}
+}
+
+func (p staticCredentialsProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	return Credentials(p), nil
+}
+
+type envCredentialsProvider struct{}
+
+// NewEnvCredentialsProvider reads SELLERCENTER_USER and SELLERCENTER_KEY for every request.
+func NewEnvCredentialsProvider() CredentialsProvider {
+	return envCredentialsProvider{}
+}
+
+func (envCredentialsProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	user, key := os.Getenv(EnvUser), os.Getenv(EnvKey)
+	if user == "" || key == "" {
+		return Credentials{}, fmt.Errorf("%w: %s and %s must be set", ErrNoCredentials, EnvUser, EnvKey)
+	}
+
+	return Credentials{User: user, Key: key}, nil
+}
+
+// FileCredentialsProvider reads the user and key of a profile file, see LoadClientConfigProfile, and reads
+// it again whenever its modification time changes.
+type FileCredentialsProvider struct {
+	path    string
+	profile string
+
+	mu          sync.Mutex
+	modTime     time.Time
+	credentials Credentials
+}
+
+func NewFileCredentialsProvider(path string, profile string) *FileCredentialsProvider {
+	return &FileCredentialsProvider{path: path, profile: profile}
+}
+
+func (p *FileCredentialsProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if info.ModTime().Equal(p.modTime) {
+		return p.credentials, nil
+	}
+
+	config, err := readProfile(p.path, p.profile)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	if config.User == "" || config.Key == "" {
+		return Credentials{}, fmt.Errorf("%w: user and key must be set in %s", ErrNoCredentials, p.path)
+	}
+
+	p.modTime = info.ModTime()
+	p.credentials = Credentials{User: config.User, Key: config.Key}
+
+	return p.credentials, nil
+}
+
+type processCredentialsProvider struct {
+	command string
+	args    []string
+}
+
+// NewProcessCredentialsProvider runs the command for the credentials. It has to print a JSON object with
+// User, Key and optionally Expiration in RFC 3339 to stdout, e.g. {"User": "...", "Key": "...",
+// "Expiration": "2020-01-01T12:00:00Z"}. Wrap it with a CachedCredentialsProvider to not run it for
+// every request.
+func NewProcessCredentialsProvider(command string, args ...string) CredentialsProvider {
+	return processCredentialsProvider{command: command, args: args}
+}
+
+func (p processCredentialsProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, p.command, p.args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return Credentials{}, fmt.Errorf("credentials process %s failed: %w: %s", p.command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var output struct {
+		User       string
+		Key        string
+		Expiration *time.Time
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return Credentials{}, fmt.Errorf("credentials process %s printed invalid JSON: %w", p.command, err)
+	}
+
+	if output.User == "" || output.Key == "" {
+		return Credentials{}, fmt.Errorf("%w: credentials process %s printed no User or Key", ErrNoCredentials, p.command)
+	}
+
+	credentials := Credentials{User: output.User, Key: output.Key}
+	if output.Expiration != nil {
+		credentials.Expires = *output.Expiration
+	}
+
+	return credentials, nil
+}
+
+// CachedCredentialsProvider caches the credentials of another provider for TTL, or until ExpiryWindow before
+// they expire if that is earlier. If refreshing fails, credentials that did not expire yet are kept, so a
+// rotated key is picked up without failing requests in between.
+//
+// Only one refresh runs at a time, detached from the context of the caller that started it. The caller waits
+// for it, while other callers are served the cached credentials until they expire.
+type CachedCredentialsProvider struct {
+	Provider     CredentialsProvider
+	TTL          time.Duration
+	ExpiryWindow time.Duration
+	Clock        Clock
+
+	mu          sync.Mutex
+	credentials Credentials
+	refreshAt   time.Time
+	cached      bool
+	refreshing  *credentialsRefresh
+}
+
+// credentialsRefresh is the result of a refresh, set before done is closed.
+type credentialsRefresh struct {
+	done        chan struct{}
+	credentials Credentials
+	err         error
+}
+
+func NewCachedCredentialsProvider(provider CredentialsProvider, ttl time.Duration) *CachedCredentialsProvider {
+	return &CachedCredentialsProvider{
+		Provider:     provider,
+		TTL:          ttl,
+		ExpiryWindow: time.Minute,
+		Clock:        SystemClock,
+	}
+}
+
+func (p *CachedCredentialsProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+
+	now := p.now()
+	if p.cached && now.Before(p.refreshAt) {
+		credentials := p.credentials
+		p.mu.Unlock()
+
+		return credentials, nil
+	}
+
+	refresh := p.refreshing
+	if refresh == nil {
+		refresh = &credentialsRefresh{done: make(chan struct{})}
+		p.refreshing = refresh
+
+		go p.refresh(refresh)
+	} else if p.cached && !p.credentials.expired(now) {
+		credentials := p.credentials
+		p.mu.Unlock()
+
+		return credentials, nil
+	}
+
+	p.mu.Unlock()
+
+	select {
+	case <-refresh.done:
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-refresh.done:
+		if refresh.err == nil {
+			return refresh.credentials, nil
+		}
+	default:
+	}
+
+	if p.cached && !p.credentials.expired(p.now()) {
+		return p.credentials, nil
+	}
+
+	if refresh.err != nil {
+		return Credentials{}, refresh.err
+	}
+
+	return Credentials{}, ctx.Err()
+}
+
+// refresh asks the wrapped provider without the context of a caller, so a canceled call does not fail the
+// refresh for the callers waiting for it.
+func (p *CachedCredentialsProvider) refresh(refresh *credentialsRefresh) {
+	credentials, err := p.Provider.Retrieve(context.Background())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	refresh.credentials, refresh.err = credentials, err
+	if err == nil {
+		refreshAt := p.now().Add(p.TTL)
+		if !credentials.Expires.IsZero() {
+			if expiresAt := credentials.Expires.Add(-p.ExpiryWindow); expiresAt.Before(refreshAt) {
+				refreshAt = expiresAt
+			}
+		}
+
+		p.credentials, p.refreshAt, p.cached = credentials, refreshAt, true
+	}
+
+	p.refreshing = nil
+	close(refresh.done)
+}
+
+func (p *CachedCredentialsProvider) now() time.Time {
+	if p.Clock == nil {
+		return SystemClock.Now()
+	}
+
+	return p.Clock.Now()
+}
+
+// Invalidate makes the next Retrieve ask the wrapped provider, e.g. after the API rejected the key.
+func (p *CachedCredentialsProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.refreshAt = time.Time{}
+}
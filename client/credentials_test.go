@@ -0,0 +1,240 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type sequenceCredentialsProvider struct {
+	calls       int32
+	credentials []Credentials
+	errs        []error
+}
+
+func (p *sequenceCredentialsProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	i := int(atomic.AddInt32(&p.calls, 1)) - 1
+
+	return p.credentials[i], p.errs[i]
+}
+
+func Test_Client_Signs_With_Rotated_Key_From_Cached_Provider(t *testing.T) {
+	var currentKey atomic.Value
+	currentKey.Store("old-key")
+
+	keys := func(user string) (string, bool) {
+		return currentKey.Load().(string), user == "abc@sellercenter.net"
+	}
+
+	server := httptest.NewServer(SignatureMiddleware(keys, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "", "RequestAction": "GetBrands", "ResponseType": "", "Timestamp": ""}, "Body": ""}}`))
+	})))
+	defer server.Close()
+
+	provider := &sequenceCredentialsProvider{
+		credentials: []Credentials{{User: "abc@sellercenter.net", Key: "old-key"}, {User: "abc@sellercenter.net", Key: "new-key"}},
+		errs:        []error{nil, nil},
+	}
+
+	now := &fakeNow{now: time.Now()}
+	cached := NewCachedCredentialsProvider(provider, time.Minute)
+	cached.Clock = now
+
+	c := NewClient(clientConfig{Url: server.URL}, nil, WithCredentialsProvider(cached))
+
+	call := func() Response {
+		response, err := c.Call(NewGenericRequest("GetBrands", MethodGET))
+		if err != nil {
+			t.Fatalf("unexpected error `%v`.", err)
+		}
+
+		return response
+	}
+
+	if call().IsError() || call().IsError() {
+		t.Fatal("expected calls signed with the old key to succeed.")
+	}
+
+	currentKey.Store("new-key")
+	if !call().IsError() {
+		t.Fatal("expected call with the cached old key to fail.")
+	}
+
+	now.Add(time.Minute)
+	if call().IsError() {
+		t.Fatal("expected call with the rotated key to succeed.")
+	}
+
+	if calls := atomic.LoadInt32(&provider.calls); calls != 2 {
+		t.Fatalf("expected provider to be asked twice, actual %d.", calls)
+	}
+}
+
+func Test_Cached_Credentials_Provider_Keeps_Valid_Credentials_On_Refresh_Error(t *testing.T) {
+	start := time.Now()
+	now := &fakeNow{now: start}
+
+	provider := &sequenceCredentialsProvider{
+		credentials: []Credentials{{User: "u", Key: "k", Expires: start.Add(10 * time.Minute)}, {}, {}},
+		errs:        []error{nil, errors.New("unavailable"), errors.New("unavailable")},
+	}
+
+	cached := NewCachedCredentialsProvider(provider, time.Hour)
+	cached.Clock = now
+
+	if credentials, err := cached.Retrieve(context.Background()); err != nil || credentials.Key != "k" {
+		t.Fatalf("unexpected credentials `%+v` `%v`.", credentials, err)
+	}
+
+	// refresh is due within the expiry window, the failed refresh keeps the valid credentials
+	now.Add(9*time.Minute + 30*time.Second)
+	if credentials, err := cached.Retrieve(context.Background()); err != nil || credentials.Key != "k" {
+		t.Fatalf("expected cached credentials, actual `%+v` `%v`.", credentials, err)
+	}
+
+	now.Add(time.Minute)
+	if _, err := cached.Retrieve(context.Background()); err == nil {
+		t.Fatal("expected error once the credentials expired.")
+	}
+}
+
+type blockingCredentialsProvider struct {
+	calls   int32
+	release chan struct{}
+}
+
+func (p *blockingCredentialsProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	if atomic.AddInt32(&p.calls, 1) > 1 {
+		<-p.release
+	}
+
+	return Credentials{User: "u", Key: fmt.Sprintf("key-%d", atomic.LoadInt32(&p.calls))}, ctx.Err()
+}
+
+func Test_Cached_Credentials_Provider_Serves_Cached_Credentials_During_Refresh(t *testing.T) {
+	now := &fakeNow{now: time.Now()}
+	provider := &blockingCredentialsProvider{release: make(chan struct{})}
+
+	// ... a struct literal without Clock uses the system clock until it is set
+	cached := &CachedCredentialsProvider{Provider: provider, TTL: time.Minute}
+	if credentials, err := cached.Retrieve(context.Background()); err != nil || credentials.Key != "key-1" {
+		t.Fatalf("unexpected credentials `%+v` `%v`.", credentials, err)
+	}
+
+	cached.Clock = now
+	now.Add(time.Hour)
+
+	// ... the caller starting the refresh gives up, but the refresh goes on without its context
+	ctx, cancel := context.WithCancel(context.Background())
+	refreshed := make(chan Credentials)
+	go func() {
+		credentials, _ := cached.Retrieve(ctx)
+		refreshed <- credentials
+	}()
+
+	for atomic.LoadInt32(&provider.calls) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 0; i < 3; i++ {
+		if credentials, err := cached.Retrieve(context.Background()); err != nil || credentials.Key != "key-1" {
+			t.Fatalf("expected cached credentials during refresh, actual `%+v` `%v`.", credentials, err)
+		}
+	}
+
+	cancel()
+	if credentials := <-refreshed; credentials.Key != "key-1" {
+		t.Fatalf("expected cached credentials for canceled caller, actual `%+v`.", credentials)
+	}
+
+	close(provider.release)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		credentials, err := cached.Retrieve(context.Background())
+		if err == nil && credentials.Key == "key-2" {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected refreshed credentials, actual `%+v` `%v`.", credentials, err)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	if calls := atomic.LoadInt32(&provider.calls); calls != 2 {
+		t.Fatalf("expected a single refresh, actual %d calls.", calls)
+	}
+}
+
+func Test_File_Credentials_Provider_Reloads_Changed_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "credentials")
+	write := func(key string, modTime time.Time) {
+		if err := ioutil.WriteFile(path, []byte("[default]\nuser = abc@sellercenter.net\nkey = "+key+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	modTime := time.Now().Add(-time.Hour)
+	write("old-key", modTime)
+
+	provider := NewFileCredentialsProvider(path, "")
+	if credentials, err := provider.Retrieve(context.Background()); err != nil || credentials.Key != "old-key" {
+		t.Fatalf("unexpected credentials `%+v` `%v`.", credentials, err)
+	}
+
+	write("new-key", modTime.Add(time.Minute))
+	if credentials, err := provider.Retrieve(context.Background()); err != nil || credentials.Key != "new-key" {
+		t.Fatalf("expected rotated key, actual `%+v` `%v`.", credentials, err)
+	}
+}
+
+func Test_Process_Credentials_Provider_Reads_Command_Output(t *testing.T) {
+	provider := NewProcessCredentialsProvider("sh", "-c", `echo '{"User": "abc@sellercenter.net", "Key": "1234567890", "Expiration": "2030-01-01T00:00:00Z"}'`)
+
+	credentials, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	expires, _ := time.Parse(time.RFC3339, "2030-01-01T00:00:00Z")
+	if credentials.User != "abc@sellercenter.net" || credentials.Key != "1234567890" || !credentials.Expires.Equal(expires) {
+		t.Fatalf("unexpected credentials `%+v`.", credentials)
+	}
+
+	if _, err := NewProcessCredentialsProvider("sh", "-c", "echo denied >&2; exit 1").Retrieve(context.Background()); err == nil {
+		t.Fatal("expected error for failing command.")
+	}
+}
+
+func Test_Env_Credentials_Provider_Requires_User_And_Key(t *testing.T) {
+	defer setEnv(t, map[string]string{EnvUser: "abc@sellercenter.net", EnvKey: ""})()
+
+	if _, err := NewEnvCredentialsProvider().Retrieve(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, actual `%v`.", err)
+	}
+
+	os.Setenv(EnvKey, "1234567890")
+	if credentials, err := NewEnvCredentialsProvider().Retrieve(context.Background()); err != nil || credentials.Key != "1234567890" {
+		t.Fatalf("unexpected credentials `%+v` `%v`.", credentials, err)
+	}
+}
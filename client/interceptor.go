@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Handler performs a single Seller Center call.
+type Handler func(ctx context.Context, request Request) (Response, error)
+
+// Interceptor wraps a Handler to add behaviour around a call, e.g. headers, audit logging or metrics.
+type Interceptor func(next Handler) Handler
+
+// chainInterceptors wraps the handler so that the first interceptor is the outermost one.
+func chainInterceptors(handler Handler, interceptors ...Interceptor) Handler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		if interceptors[i] != nil {
+			handler = interceptors[i](handler)
+		}
+	}
+
+	return handler
+}
+
+// NewLoggingInterceptor logs the outcome of every call to a *log.Logger at info level.
+func NewLoggingInterceptor(l *log.Logger) Interceptor {
+	return NewLeveledLoggingInterceptor(NewStdLogger(l, LevelInfo))
+}
+
+// NewLeveledLoggingInterceptor logs the outcome of every call. NewClient installs it with the client logger
+// unless it is replaced with WithLoggingInterceptor. Response heads are only logged at debug level.
+func NewLeveledLoggingInterceptor(logger Logger) Interceptor {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request Request) (Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, request)
+
+			keyvals := []interface{}{
+				"action", request.GetRequestParams().Get(fieldAction),
+				"method", request.GetMethod(),
+				"duration", time.Since(start),
+			}
+
+			switch {
+			case err != nil:
+				logger.Error("Call response", append(keyvals, "error", err)...)
+			case resp.IsError():
+				head, _ := resp.GetHeadObject().(HeadErrorResponse)
+				logger.Warn("Call response", append(keyvals, "errorCode", head.ErrorCode, "errorMessage", head.ErrorMessage)...)
+			default:
+				logger.Info("Call response", keyvals...)
+				logger.Debug("Call response head", append(keyvals, "head", string(resp.GetHead()))...)
+			}
+
+			return resp, err
+		}
+	}
+}
+
+type headerContextKey struct{}
+
+// ContextWithHeader returns a copy of ctx that makes the client send the header with the HTTP request.
+func ContextWithHeader(ctx context.Context, key string, value string) context.Context {
+	header := headerFromContext(ctx).Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	header.Add(key, value)
+
+	return context.WithValue(ctx, headerContextKey{}, header)
+}
+
+func headerFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headerContextKey{}).(http.Header)
+
+	return header
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Interceptors_Are_Called_In_Order(t *testing.T) {
+	calls := make([]string, 0)
+
+	createInterceptor := func(name string) Interceptor {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, request Request) (Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next(ctx, request)
+				calls = append(calls, name+" after")
+
+				return resp, err
+			}
+		}
+	}
+
+	handler := chainInterceptors(func(ctx context.Context, request Request) (Response, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	}, createInterceptor("first"), createInterceptor("second"))
+
+	handler(context.Background(), NewGenericRequest("GetOrders", MethodGET))
+
+	expected := []string{"first before", "second before", "handler", "second after", "first after"}
+	if !reflect.DeepEqual(expected, calls) {
+		t.Fatalf("interceptors were not called in order. expected: `%v` - actual: `%v`.", expected, calls)
+	}
+}
+
+func Test_Interceptors_Can_Add_Headers_And_Mutate_Requests(t *testing.T) {
+	var header http.Header
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "1"}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	interceptor := func(next Handler) Handler {
+		return func(ctx context.Context, request Request) (Response, error) {
+			request.SetRequestParam("Limit", "10")
+
+			return next(ContextWithHeader(ctx, "X-Audit-Id", "42"), request)
+		}
+	}
+
+	c := createTestClient(server.URL, WithInterceptors(interceptor))
+
+	for _, method := range []string{MethodGET, MethodPOST} {
+		if _, err := c.Call(NewGenericRequest("GetOrders", method)); err != nil {
+			t.Fatalf("expected error to be nil, actual `%s`.", err)
+		}
+
+		if header.Get("X-Audit-Id") != "42" {
+			t.Fatalf("expected %s request to carry the X-Audit-Id header, actual `%v`.", method, header)
+		}
+
+		if !strings.Contains(query, "Limit=10") {
+			t.Fatalf("expected %s request to carry the Limit param, actual `%s`.", method, query)
+		}
+	}
+}
+
+func Test_Logging_Interceptor_Can_Be_Replaced(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "1"}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	var defaultOutput, replacedOutput bytes.Buffer
+
+	c := NewClient(
+		clientConfig{Url: server.URL, User: "abc@sellercenter.net", Key: "1234567890"},
+		log.New(&defaultOutput, "", 0),
+		WithLoggingInterceptor(NewLoggingInterceptor(log.New(&replacedOutput, "", 0))),
+	)
+
+	if _, err := c.Call(NewGenericRequest("GetOrders", MethodGET)); err != nil {
+		t.Fatalf("expected error to be nil, actual `%s`.", err)
+	}
+
+	if strings.Contains(defaultOutput.String(), "Call response") {
+		t.Fatal("expected default logging interceptor to be replaced.")
+	}
+
+	if !strings.Contains(replacedOutput.String(), "Call response") {
+		t.Fatal("expected replaced logging interceptor to log the call.")
+	}
+}
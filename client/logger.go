@@ -0,0 +1,154 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	}
+
+	return "error"
+}
+
+// Logger is a leveled logger taking alternating key/value fields,
+// e.g. logger.Info("Call response", "action", "GetOrders", "status", 200).
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// NewStdLogger adapts a *log.Logger. Messages below the given level are dropped, a nil logger drops everything.
+func NewStdLogger(l *log.Logger, level LogLevel) Logger {
+	return stdLogger{logger: l, level: level}
+}
+
+type stdLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+func (sl stdLogger) Debug(msg string, keyvals ...interface{}) {
+	sl.log(LevelDebug, msg, keyvals)
+}
+
+func (sl stdLogger) Info(msg string, keyvals ...interface{}) {
+	sl.log(LevelInfo, msg, keyvals)
+}
+
+func (sl stdLogger) Warn(msg string, keyvals ...interface{}) {
+	sl.log(LevelWarn, msg, keyvals)
+}
+
+func (sl stdLogger) Error(msg string, keyvals ...interface{}) {
+	sl.log(LevelError, msg, keyvals)
+}
+
+func (sl stdLogger) log(level LogLevel, msg string, keyvals []interface{}) {
+	if sl.logger == nil || level < sl.level {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "level=%s msg=%s", level, formatLogValue(msg))
+
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		fmt.Fprintf(&b, " %v=%s", keyvals[i], formatLogValue(value))
+	}
+
+	sl.logger.Println(b.String())
+}
+
+func formatLogValue(value interface{}) string {
+	s := fmt.Sprintf("%v", value)
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return fmt.Sprintf("%q", s)
+	}
+
+	return s
+}
+
+const redacted = "[REDACTED]"
+
+// DefaultRedactedFields are the payload fields a client redacts unless WithRedactedFields replaces them: prices
+// and the product data of POST requests.
+var DefaultRedactedFields = []string{"Price", "SalePrice", "ProductData"}
+
+// Redactor removes secrets from logged URLs and payloads. The Signature and UserID query params are always
+// redacted, payload fields are XML elements like `Price` or `Description` whose content is replaced.
+type Redactor struct {
+	params        []string
+	payloadFields []*regexp.Regexp
+}
+
+func NewRedactor(payloadFields ...string) *Redactor {
+	r := &Redactor{params: []string{fieldSignature, fieldUserId}}
+	for _, field := range payloadFields {
+		name := regexp.QuoteMeta(field)
+		r.payloadFields = append(r.payloadFields, regexp.MustCompile(`(?s)(<`+name+`(?:\s[^>]*)?>).*?(</`+name+`>)`))
+	}
+
+	return r
+}
+
+func (r *Redactor) RedactUrl(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return redacted
+	}
+
+	query := u.Query()
+	for _, param := range r.params {
+		if _, ok := query[param]; ok {
+			query.Set(param, redacted)
+		}
+	}
+
+	u.RawQuery = strings.Replace(query.Encode(), "+", "%20", -1)
+
+	return u.String()
+}
+
+// redactUrlError redacts the url of the *url.Error the http.Client returns, which holds the signed url.
+func (r *Redactor) redactUrlError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+
+	return &url.Error{Op: urlErr.Op, URL: r.RedactUrl(urlErr.URL), Err: urlErr.Err}
+}
+
+func (r *Redactor) RedactPayload(payload []byte) string {
+	for _, field := range r.payloadFields {
+		payload = field.ReplaceAll(payload, []byte("${1}"+redacted+"${2}"))
+	}
+
+	return string(payload)
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Std_Logger_Writes_Key_Values_Above_Level(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewStdLogger(log.New(&output, "", 0), LevelInfo)
+
+	logger.Debug("hidden", "action", "GetOrders")
+	logger.Warn("Call failed", "action", "GetOrders", "error", "connection reset")
+
+	expected := "level=warn msg=\"Call failed\" action=GetOrders error=\"connection reset\"\n"
+	if output.String() != expected {
+		t.Fatalf("unexpected log output. expected: `%s` - actual: `%s`.", expected, output.String())
+	}
+}
+
+func Test_Redactor_Redacts_Url_Secrets(t *testing.T) {
+	redactor := NewRedactor()
+
+	redactedUrl := redactor.RedactUrl("https://my-api.sc.net/?Action=GetOrders&Signature=bb6b9ee192fe&Timestamp=2014-11-12T11%3A45%3A26Z&UserID=abc%40sellercenter.net")
+
+	expected := "https://my-api.sc.net/?Action=GetOrders&Signature=%5BREDACTED%5D&Timestamp=2014-11-12T11%3A45%3A26Z&UserID=%5BREDACTED%5D"
+	if redactedUrl != expected {
+		t.Fatalf("can not redact url. expected: `%s` - actual: `%s`.", expected, redactedUrl)
+	}
+}
+
+func Test_Redactor_Redacts_Payload_Fields(t *testing.T) {
+	redactor := NewRedactor("Price", "Description")
+
+	payload := []byte("<Request><Product><SellerSku>sku-1</SellerSku><Price>12.99</Price><Description><![CDATA[secret\ntext]]></Description></Product></Request>")
+
+	expected := "<Request><Product><SellerSku>sku-1</SellerSku><Price>[REDACTED]</Price><Description>[REDACTED]</Description></Product></Request>"
+	if redacted := redactor.RedactPayload(payload); redacted != expected {
+		t.Fatalf("can not redact payload. expected: `%s` - actual: `%s`.", expected, redacted)
+	}
+}
+
+func Test_Client_Logs_Payload_Only_At_Debug_Level(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "1"}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	type product struct {
+		SellerSku string
+		Price     float64
+	}
+
+	for _, level := range []LogLevel{LevelInfo, LevelDebug} {
+		var output bytes.Buffer
+
+		c := createTestClient(server.URL, WithLogger(NewStdLogger(log.New(&output, "", 0), level)), WithRedactedFields("Price"))
+
+		request := NewGenericRequest("ProductCreate", MethodPOST)
+		request.SetPostData(product{SellerSku: "sku-1", Price: 4711.0815})
+
+		if _, err := c.Call(request); err != nil {
+			t.Fatalf("expected error to be nil, actual `%s`.", err)
+		}
+
+		logged := output.String()
+
+		if strings.Contains(logged, "1234567890") || strings.Contains(logged, "abc%40sellercenter.net") || strings.Contains(logged, "4711.0815") {
+			t.Fatalf("expected secrets to be redacted, actual `%s`.", logged)
+		}
+
+		if strings.Contains(logged, "sku-1") != (level == LevelDebug) {
+			t.Fatalf("expected payload to be logged only at debug level, actual `%s`.", logged)
+		}
+	}
+}
+
+func Test_Client_Redacts_Signed_Url_Of_Network_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var output bytes.Buffer
+	policy := NewExponentialBackoffRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	c := createTestClient(server.URL, WithLogger(NewStdLogger(log.New(&output, "", 0), LevelDebug)), WithRetryPolicy(policy))
+
+	_, err := c.Call(NewGenericRequest("GetOrders", MethodGET))
+	if err == nil {
+		t.Fatal("expected network error.")
+	}
+
+	signature := regexp.MustCompile(`Signature=[0-9a-f]{64}`)
+
+	for _, logged := range []string{output.String(), err.Error()} {
+		if signature.MatchString(logged) || strings.Contains(logged, "abc%40sellercenter.net") {
+			t.Fatalf("expected signed url to be redacted, actual `%s`.", logged)
+		}
+	}
+
+	if !strings.Contains(output.String(), "Signature=%5BREDACTED%5D") {
+		t.Fatalf("expected redacted url to be logged, actual `%s`.", output.String())
+	}
+}
+
+func Test_Client_Redacts_Default_Payload_Fields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "1"}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	type product struct {
+		SellerSku   string
+		Price       float64
+		ProductData struct{ Description string }
+	}
+
+	var output bytes.Buffer
+	c := createTestClient(server.URL, WithLogger(NewStdLogger(log.New(&output, "", 0), LevelDebug)))
+
+	data := product{SellerSku: "sku-1", Price: 4711.0815}
+	data.ProductData.Description = "unreleased collection"
+
+	request := NewGenericRequest("ProductCreate", MethodPOST)
+	request.SetPostData(data)
+
+	if _, err := c.Call(request); err != nil {
+		t.Fatalf("expected error to be nil, actual `%s`.", err)
+	}
+
+	if logged := output.String(); strings.Contains(logged, "4711.0815") || strings.Contains(logged, "unreleased collection") || !strings.Contains(logged, "sku-1") {
+		t.Fatalf("expected price and product data to be redacted, actual `%s`.", logged)
+	}
+}
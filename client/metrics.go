@@ -0,0 +1,314 @@
+package client
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds in seconds of the call latency histogram.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// MetricsCollector receives one observation per call made through Client.Call.
+type MetricsCollector interface {
+	ObserveCall(metrics CallMetrics)
+}
+
+// CallMetrics describes a finished call. StatusCodes holds the HTTP status of every attempt, 0 for attempts
+// without response, StatusCode is the one of the last attempt. ErrorCode is set for ErrorResponses.
+// Labels are the ones set with WithMetricsLabels.
+type CallMetrics struct {
+	Action      string
+	Method      string
+	Labels      map[string]string
+	StatusCode  int
+	StatusCodes []int
+	ErrorCode   string
+	Attempts    int
+	Duration    time.Duration
+	Err         error
+}
+
+// callStats collects what happened during the attempts of a single call.
+type callStats struct {
+	attempts    int
+	statusCodes []int
+}
+
+func (cs *callStats) observeAttempt(response *http.Response) {
+	cs.attempts++
+
+	statusCode := 0
+	if response != nil {
+		statusCode = response.StatusCode
+	}
+
+	cs.statusCodes = append(cs.statusCodes, statusCode)
+}
+
+func (cs *callStats) lastStatusCode() int {
+	if len(cs.statusCodes) == 0 {
+		return 0
+	}
+
+	return cs.statusCodes[len(cs.statusCodes)-1]
+}
+
+func newCallMetrics(request Request, resp Response, err error, stats *callStats, duration time.Duration, labels map[string]string) CallMetrics {
+	metrics := CallMetrics{
+		Action:      request.GetRequestParams().Get(fieldAction),
+		Method:      request.GetMethod(),
+		Labels:      labels,
+		StatusCode:  stats.lastStatusCode(),
+		StatusCodes: stats.statusCodes,
+		Attempts:    stats.attempts,
+		Duration:    duration,
+		Err:         err,
+	}
+
+	if resp != nil && resp.IsError() {
+		head, _ := resp.GetHeadObject().(HeadErrorResponse)
+		metrics.ErrorCode = head.ErrorCode
+	}
+
+	return metrics
+}
+
+// ExpvarMetrics is a MetricsCollector keeping counters and latency histograms per action and method.
+// They are published through expvar and can be scraped in Prometheus text format with PrometheusHandler.
+type ExpvarMetrics struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*callSeries
+}
+
+type callSeries struct {
+	labels      [][2]string
+	calls       int64
+	errors      int64
+	attempts    int64
+	statusCodes map[int]int64
+	errorCodes  map[string]int64
+	latency     []int64
+	latencySum  float64
+}
+
+// NewExpvarMetrics publishes the metrics as expvar variable with the given name. Like expvar.Publish it
+// panics if the name is already in use. An empty name skips publishing.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		buckets: DefaultLatencyBuckets,
+		series:  map[string]*callSeries{},
+	}
+
+	if name != "" {
+		expvar.Publish(name, expvar.Func(m.snapshot))
+	}
+
+	return m
+}
+
+func (m *ExpvarMetrics) ObserveCall(metrics CallMetrics) {
+	labels := [][2]string{{"action", metrics.Action}, {"method", metrics.Method}}
+	for _, name := range sortedKeys(metrics.Labels) {
+		labels = append(labels, [2]string{name, metrics.Labels[name]})
+	}
+	key := formatLabels(labels)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series, ok := m.series[key]
+	if !ok {
+		series = &callSeries{
+			labels:      labels,
+			statusCodes: map[int]int64{},
+			errorCodes:  map[string]int64{},
+			latency:     make([]int64, len(m.buckets)),
+		}
+		m.series[key] = series
+	}
+
+	series.calls++
+	series.attempts += int64(metrics.Attempts)
+
+	if metrics.Err != nil {
+		series.errors++
+	}
+
+	for _, statusCode := range metrics.StatusCodes {
+		series.statusCodes[statusCode]++
+	}
+
+	if metrics.ErrorCode != "" {
+		series.errorCodes[metrics.ErrorCode]++
+	}
+
+	seconds := metrics.Duration.Seconds()
+	series.latencySum += seconds
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			series.latency[i]++
+		}
+	}
+}
+
+func (m *ExpvarMetrics) snapshot() interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := map[string]interface{}{}
+	for key, series := range m.series {
+		statusCodes := map[string]int64{}
+		for statusCode, count := range series.statusCodes {
+			statusCodes[strconv.Itoa(statusCode)] = count
+		}
+
+		latency := map[string]int64{}
+		for i, bound := range m.buckets {
+			latency[formatFloat(bound)] = series.latency[i]
+		}
+
+		snapshot[key] = map[string]interface{}{
+			"calls":       series.calls,
+			"errors":      series.errors,
+			"attempts":    series.attempts,
+			"retries":     series.attempts - series.calls,
+			"statusCodes": statusCodes,
+			"errorCodes":  copyCounts(series.errorCodes),
+			"latency": map[string]interface{}{
+				"buckets": latency,
+				"sum":     series.latencySum,
+				"count":   series.calls,
+			},
+		}
+	}
+
+	return snapshot
+}
+
+// PrometheusHandler serves the metrics in the Prometheus text exposition format.
+func (m *ExpvarMetrics) PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.writePrometheus(w)
+	})
+}
+
+func (m *ExpvarMetrics) writePrometheus(w http.ResponseWriter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writeHeader := func(name string, metricType string, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	}
+
+	writeHeader("sellercenter_calls_total", "counter", "Seller Center calls.")
+	for _, key := range keys {
+		fmt.Fprintf(w, "sellercenter_calls_total{%s} %d\n", key, m.series[key].calls)
+	}
+
+	writeHeader("sellercenter_call_errors_total", "counter", "Seller Center calls that returned an error, e.g. network errors and HTTPErrors.")
+	for _, key := range keys {
+		fmt.Fprintf(w, "sellercenter_call_errors_total{%s} %d\n", key, m.series[key].errors)
+	}
+
+	writeHeader("sellercenter_attempts_total", "counter", "Seller Center HTTP attempts including retries.")
+	for _, key := range keys {
+		fmt.Fprintf(w, "sellercenter_attempts_total{%s} %d\n", key, m.series[key].attempts)
+	}
+
+	writeHeader("sellercenter_http_responses_total", "counter", "Seller Center HTTP responses by status, 0 for none.")
+	for _, key := range keys {
+		series := m.series[key]
+
+		statusCodes := make([]int, 0, len(series.statusCodes))
+		for statusCode := range series.statusCodes {
+			statusCodes = append(statusCodes, statusCode)
+		}
+		sort.Ints(statusCodes)
+
+		for _, statusCode := range statusCodes {
+			fmt.Fprintf(w, "sellercenter_http_responses_total{%s} %d\n", withLabel(series.labels, "status", strconv.Itoa(statusCode)), series.statusCodes[statusCode])
+		}
+	}
+
+	writeHeader("sellercenter_api_errors_total", "counter", "Seller Center ErrorResponses by error code.")
+	for _, key := range keys {
+		series := m.series[key]
+
+		errorCodes := make([]string, 0, len(series.errorCodes))
+		for errorCode := range series.errorCodes {
+			errorCodes = append(errorCodes, errorCode)
+		}
+		sort.Strings(errorCodes)
+
+		for _, errorCode := range errorCodes {
+			fmt.Fprintf(w, "sellercenter_api_errors_total{%s} %d\n", withLabel(series.labels, "code", errorCode), series.errorCodes[errorCode])
+		}
+	}
+
+	writeHeader("sellercenter_call_duration_seconds", "histogram", "Seller Center call latency including retries.")
+	for _, key := range keys {
+		series := m.series[key]
+
+		for i, bound := range m.buckets {
+			fmt.Fprintf(w, "sellercenter_call_duration_seconds_bucket{%s} %d\n", withLabel(series.labels, "le", formatFloat(bound)), series.latency[i])
+		}
+
+		fmt.Fprintf(w, "sellercenter_call_duration_seconds_bucket{%s} %d\n", withLabel(series.labels, "le", "+Inf"), series.calls)
+		fmt.Fprintf(w, "sellercenter_call_duration_seconds_sum{%s} %s\n", key, formatFloat(series.latencySum))
+		fmt.Fprintf(w, "sellercenter_call_duration_seconds_count{%s} %d\n", key, series.calls)
+	}
+}
+
+func formatLabels(labels [][2]string) string {
+	pairs := make([]string, len(labels))
+	for i, label := range labels {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, label[0], labelValueEscaper.Replace(label[1]))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// labelValueEscaper escapes label values the way the Prometheus text format expects, other characters are kept as they are.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func withLabel(labels [][2]string, name string, value string) string {
+	return formatLabels(append(labels[:len(labels):len(labels)], [2]string{name, value}))
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func copyCounts(counts map[string]int64) map[string]int64 {
+	c := make(map[string]int64, len(counts))
+	for k, v := range counts {
+		c[k] = v
+	}
+
+	return c
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type recordingCollector struct {
+	mu    sync.Mutex
+	calls []CallMetrics
+}
+
+func (rc *recordingCollector) ObserveCall(metrics CallMetrics) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.calls = append(rc.calls, metrics)
+}
+
+func Test_Can_Observe_Call_Metrics(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(`{"ErrorResponse": {"Head": {"RequestAction": "GetProducts", "ErrorType": "Sender", "ErrorCode": "5", "ErrorMessage": "E005: Invalid Request Format"}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	retryPolicy := NewExponentialBackoffRetryPolicy()
+	retryPolicy.BaseDelay = time.Millisecond
+
+	collector := &recordingCollector{}
+	c := createTestClient(server.URL, WithRetryPolicy(retryPolicy), WithMetricsCollector(collector))
+
+	if _, err := c.Call(NewGenericRequest("GetProducts", MethodGET)); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if len(collector.calls) != 1 {
+		t.Fatalf("expected 1 observed call, actual %d.", len(collector.calls))
+	}
+
+	observed := collector.calls[0]
+	if observed.Duration <= 0 {
+		t.Fatalf("expected positive duration, actual %s.", observed.Duration)
+	}
+
+	observed.Duration = 0
+	expected := CallMetrics{
+		Action:      "GetProducts",
+		Method:      MethodGET,
+		StatusCode:  http.StatusOK,
+		StatusCodes: []int{http.StatusServiceUnavailable, http.StatusOK},
+		ErrorCode:   "5",
+		Attempts:    2,
+	}
+
+	if !reflect.DeepEqual(observed, expected) {
+		t.Fatalf("can not observe call. expected: `%+v` - actual: `%+v`.", expected, observed)
+	}
+}
+
+func Test_Can_Write_Expvar_Metrics_In_Prometheus_Format(t *testing.T) {
+	metrics := NewExpvarMetrics("")
+	metrics.ObserveCall(CallMetrics{
+		Action:      "GetProducts",
+		Method:      MethodGET,
+		StatusCode:  http.StatusOK,
+		StatusCodes: []int{http.StatusServiceUnavailable, http.StatusOK},
+		Attempts:    2,
+		Duration:    200 * time.Millisecond,
+	})
+	metrics.ObserveCall(CallMetrics{
+		Action:      "GetProducts",
+		Method:      MethodGET,
+		StatusCode:  http.StatusOK,
+		StatusCodes: []int{http.StatusOK},
+		ErrorCode:   "5",
+		Attempts:    1,
+		Duration:    3 * time.Second,
+	})
+
+	metrics.ObserveCall(CallMetrics{
+		Action:      "GetBrands",
+		Method:      MethodGET,
+		Labels:      map[string]string{"tenant": "id"},
+		StatusCode:  http.StatusOK,
+		StatusCodes: []int{http.StatusOK},
+		Attempts:    1,
+	})
+
+	recorder := httptest.NewRecorder()
+	metrics.PrometheusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	output := recorder.Body.String()
+	for _, line := range []string{
+		`sellercenter_calls_total{action="GetBrands",method="GET",tenant="id"} 1`,
+		`sellercenter_calls_total{action="GetProducts",method="GET"} 2`,
+		`sellercenter_call_errors_total{action="GetProducts",method="GET"} 0`,
+		`sellercenter_attempts_total{action="GetProducts",method="GET"} 3`,
+		`sellercenter_http_responses_total{action="GetProducts",method="GET",status="200"} 2`,
+		`sellercenter_http_responses_total{action="GetProducts",method="GET",status="503"} 1`,
+		`sellercenter_api_errors_total{action="GetProducts",method="GET",code="5"} 1`,
+		`sellercenter_call_duration_seconds_bucket{action="GetProducts",method="GET",le="0.1"} 0`,
+		`sellercenter_call_duration_seconds_bucket{action="GetProducts",method="GET",le="0.25"} 1`,
+		`sellercenter_call_duration_seconds_bucket{action="GetProducts",method="GET",le="5"} 2`,
+		`sellercenter_call_duration_seconds_bucket{action="GetProducts",method="GET",le="+Inf"} 2`,
+		`sellercenter_call_duration_seconds_sum{action="GetProducts",method="GET"} 3.2`,
+		`sellercenter_call_duration_seconds_count{action="GetProducts",method="GET"} 2`,
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Fatalf("expected line `%s` in output:\n%s", line, output)
+		}
+	}
+}
+
+func Test_Prometheus_Label_Values_Are_Escaped(t *testing.T) {
+	metrics := NewExpvarMetrics("")
+	metrics.ObserveCall(CallMetrics{
+		Action:   "GetBrands",
+		Method:   MethodGET,
+		Labels:   map[string]string{"tenant": "São Paulo \"BR\"\\\n"},
+		Attempts: 1,
+	})
+
+	recorder := httptest.NewRecorder()
+	metrics.PrometheusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	line := `sellercenter_calls_total{action="GetBrands",method="GET",tenant="São Paulo \"BR\"\\\n"} 1`
+	if !strings.Contains(recorder.Body.String(), line+"\n") {
+		t.Fatalf("expected line `%s` in output:\n%s", line, recorder.Body.String())
+	}
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures optional behaviour of the client created by NewClient.
+type Option func(c *client)
+
+// WithHTTPClient uses a copy of the given http.Client instead of the default one with a 10s timeout. A nil
+// http.Client keeps the default one. WithTransport and WithTimeout apply to it regardless of their order.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		if httpClient == nil {
+			c.httpClient = newDefaultHttpClient()
+
+			return
+		}
+
+		hc := *httpClient
+		c.httpClient = &hc
+	}
+}
+
+// WithTransport sets the http.RoundTripper, e.g. for proxies, custom CA bundles, mTLS or pool sizes.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *client) {
+		c.transport = transport
+	}
+}
+
+// WithTimeout sets the timeout of a single HTTP round trip. Retries are not included.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.timeout = timeout
+		c.hasTimeout = true
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithDefaultHeader adds a header that is sent with every request.
+func WithDefaultHeader(key string, value string) Option {
+	return func(c *client) {
+		if c.defaultHeader == nil {
+			c.defaultHeader = http.Header{}
+		}
+
+		c.defaultHeader.Add(key, value)
+	}
+}
+
+// WithDefaultHeaders adds headers that are sent with every request.
+func WithDefaultHeaders(header http.Header) Option {
+	return func(c *client) {
+		for key, values := range header {
+			for _, value := range values {
+				WithDefaultHeader(key, value)(c)
+			}
+		}
+	}
+}
+
+// WithRetryPolicy replaces the default exponential backoff retry policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter limits the rate of outgoing requests per action. Every retry attempt takes a token.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// WithInterceptors adds interceptors around every call. The first interceptor is the outermost one.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithLoggingInterceptor replaces the default logging interceptor. A nil interceptor disables call logging.
+func WithLoggingInterceptor(interceptor Interceptor) Option {
+	return func(c *client) {
+		c.loggingInterceptor = interceptor
+		c.hasLoggingInterceptor = true
+	}
+}
+
+// WithLogger replaces the leveled logger that by default wraps the *log.Logger given to NewClient at info level.
+func WithLogger(logger Logger) Option {
+	return func(c *client) {
+		c.log = logger
+	}
+}
+
+// WithRedactedFields redacts the content of the given XML elements when POST data is logged at debug level,
+// instead of the DefaultRedactedFields.
+func WithRedactedFields(fields ...string) Option {
+	return func(c *client) {
+		c.redactor = NewRedactor(fields...)
+	}
+}
+
+// WithClock replaces the clock used to sign requests.
+func WithClock(clock Clock) Option {
+	return func(c *client) {
+		c.clock = clock
+	}
+}
+
+// WithResponseFormat sets the response format, FormatJSON or FormatXML, of requests that do not set their own.
+func WithResponseFormat(format string) Option {
+	return func(c *client) {
+		c.responseFormat = format
+	}
+}
+
+// WithMetricsCollector reports every call to the collector, e.g. an ExpvarMetrics.
+func WithMetricsCollector(collector MetricsCollector) Option {
+	return func(c *client) {
+		c.metrics = collector
+	}
+}
+
+// WithMetricsLabels adds labels to the metrics of every call, e.g. the tenant of the client.
+func WithMetricsLabels(labels map[string]string) Option {
+	return func(c *client) {
+		merged := make(map[string]string, len(c.metricsLabels)+len(labels))
+		for name, value := range c.metricsLabels {
+			merged[name] = value
+		}
+		for name, value := range labels {
+			merged[name] = value
+		}
+
+		c.metricsLabels = merged
+	}
+}
+
+// WithCredentialsProvider signs every request with the credentials of the provider instead of the user and
+// key of the config, e.g. a CachedCredentialsProvider to rotate keys without restarting.
+func WithCredentialsProvider(provider CredentialsProvider) Option {
+	return func(c *client) {
+		c.credentials = provider
+	}
+}
+
+// WithMaxResponseSize fails calls with ErrResponseTooLarge once a response body exceeds the given number of bytes,
+// instead of reading it into memory. Zero, the default, does not limit responses.
+func WithMaxResponseSize(maxSize int64) Option {
+	return func(c *client) {
+		c.maxResponseSize = maxSize
+	}
+}
+
+// WithCircuitBreaker fails calls fast with ErrCircuitOpen while the breaker considers Seller Center unavailable.
+// Every retry attempt is checked against the breaker, so an opening circuit also ends the retries of a call.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(c *client) {
+		c.circuitBreaker = breaker
+	}
+}
+
+// WithAuthenticator replaces the default HMAC-SHA256 query signing, e.g. with a HeaderAuthenticator for API
+// gateways. The user and key of the config and WithCredentialsProvider only apply to the default. A
+// *QuerySigningAuthenticator is copied and compensates the clock skew measured by the client, like the default.
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(c *client) {
+		c.authenticator = authenticator
+	}
+}
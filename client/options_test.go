@@ -0,0 +1,93 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, request)
+
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func Test_Client_Uses_Custom_Transport_User_Agent_And_Default_Headers(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "1"}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	transport := &recordingTransport{}
+
+	c := createTestClient(
+		server.URL,
+		WithTransport(transport),
+		WithUserAgent("my-sync-worker/1.0"),
+		WithDefaultHeader("X-Tenant", "sg"),
+	)
+
+	if _, err := c.Call(NewGenericRequest("GetProducts", MethodGET)); err != nil {
+		t.Fatalf("expected error to be nil, actual `%s`.", err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Fatalf("expected custom transport to be used once, actual %d.", len(transport.requests))
+	}
+
+	if header.Get("User-Agent") != "my-sync-worker/1.0" {
+		t.Fatalf("expected User-Agent to be set, actual `%s`.", header.Get("User-Agent"))
+	}
+
+	if header.Get("X-Tenant") != "sg" {
+		t.Fatalf("expected default header to be set, actual `%s`.", header.Get("X-Tenant"))
+	}
+}
+
+func Test_Client_Options_Configure_Http_Client(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Second}
+
+	c := createTestClient("https://sellerapi.sellercenter.net/", WithHTTPClient(httpClient), WithTimeout(time.Minute)).(*client)
+
+	if c.httpClient.Timeout != time.Minute {
+		t.Fatalf("expected timeout to be 1m, actual %s.", c.httpClient.Timeout)
+	}
+
+	if httpClient.Timeout != time.Second {
+		t.Fatal("expected the given http.Client not to be modified.")
+	}
+}
+
+func Test_Client_Options_Configure_Http_Client_In_Any_Order(t *testing.T) {
+	transport := &recordingTransport{}
+
+	c := createTestClient(
+		"https://sellerapi.sellercenter.net/",
+		WithTransport(transport),
+		WithTimeout(time.Minute),
+		WithHTTPClient(&http.Client{Timeout: time.Second}),
+	).(*client)
+
+	if c.httpClient.Timeout != time.Minute {
+		t.Fatalf("expected timeout to be 1m, actual %s.", c.httpClient.Timeout)
+	}
+
+	if c.httpClient.Transport != transport {
+		t.Fatal("expected transport to be set.")
+	}
+}
+
+func Test_Client_Uses_Default_Http_Client_For_Nil(t *testing.T) {
+	c := createTestClient("https://sellerapi.sellercenter.net/", WithHTTPClient(nil)).(*client)
+
+	if c.httpClient == nil || c.httpClient.Timeout != 10*time.Second {
+		t.Fatalf("expected the default http.Client, actual %v.", c.httpClient)
+	}
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+)
+
+// Errors
+var (
+	ErrUnknownTenant = errors.New("unknown tenant")
+)
+
+// TenantCredentials are the Seller Center account of a tenant.
+type TenantCredentials struct {
+	Url  string
+	User string
+	Key  string
+}
+
+// CredentialSource provides the accounts of the tenants of a ClientPool.
+type CredentialSource interface {
+	// Credentials returns the account of the tenant or an error wrapping ErrUnknownTenant.
+	Credentials(tenant string) (TenantCredentials, error)
+	// Tenants returns the ids of all known tenants.
+	Tenants() ([]string, error)
+}
+
+// StaticCredentials is a CredentialSource of fixed accounts by tenant id.
+type StaticCredentials map[string]TenantCredentials
+
+func (sc StaticCredentials) Credentials(tenant string) (TenantCredentials, error) {
+	credentials, ok := sc[tenant]
+	if !ok {
+		return TenantCredentials{}, fmt.Errorf("%w: %s", ErrUnknownTenant, tenant)
+	}
+
+	return credentials, nil
+}
+
+func (sc StaticCredentials) Tenants() ([]string, error) {
+	tenants := make([]string, 0, len(sc))
+	for tenant := range sc {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+
+	return tenants, nil
+}
+
+// DefaultTenantRateLimit is the limit of the RateLimiter every tenant gets unless WithTenantRateLimiter is used.
+var DefaultTenantRateLimit = RateLimit{Rate: 10, Burst: 10}
+
+type PoolOption func(p *ClientPool)
+
+// WithClientOptions applies the options to the client of every tenant. Every tenant has its own RateLimiter,
+// which replaces one set with WithRateLimiter here, so a single limiter is never shared by all tenants. Use
+// WithTenantRateLimiter to configure the limiters. Other options holding state are shared, use WithTenantOptions instead.
+func WithClientOptions(opts ...Option) PoolOption {
+	return func(p *ClientPool) {
+		p.opts = append(p.opts, opts...)
+	}
+}
+
+// WithTenantOptions applies the options returned by fn to the client of a tenant, after WithClientOptions.
+func WithTenantOptions(fn func(tenant string) []Option) PoolOption {
+	return func(p *ClientPool) {
+		p.tenantOptions = fn
+	}
+}
+
+// WithTenantRateLimiter gives the client of every tenant its own RateLimiter created by fn instead of one with the
+// DefaultTenantRateLimit. fn may return nil to not limit the tenant.
+func WithTenantRateLimiter(fn func(tenant string) *RateLimiter) PoolOption {
+	return func(p *ClientPool) {
+		p.rateLimiter = fn
+	}
+}
+
+// WithMaxConcurrency limits how many tenants ForTenants and ForAllTenants run at the same time, 0 means all.
+func WithMaxConcurrency(n int) PoolOption {
+	return func(p *ClientPool) {
+		p.maxConcurrency = n
+	}
+}
+
+// ClientPool holds a Client per tenant, created on first use from the CredentialSource. The metrics of every
+// client are labeled with `tenant`. It is safe for concurrent use.
+type ClientPool struct {
+	source         CredentialSource
+	logger         *log.Logger
+	opts           []Option
+	tenantOptions  func(tenant string) []Option
+	rateLimiter    func(tenant string) *RateLimiter
+	maxConcurrency int
+
+	mu      sync.Mutex
+	clients map[string]Client
+}
+
+func NewClientPool(source CredentialSource, l *log.Logger, opts ...PoolOption) *ClientPool {
+	p := &ClientPool{
+		source:  source,
+		logger:  l,
+		clients: map[string]Client{},
+		rateLimiter: func(string) *RateLimiter {
+			return NewRateLimiter(DefaultTenantRateLimit, RateLimitWait)
+		},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Client returns the client of the tenant, creating it if needed.
+func (p *ClientPool) Client(tenant string) (Client, error) {
+	p.mu.Lock()
+	c, ok := p.clients[tenant]
+	p.mu.Unlock()
+
+	if ok {
+		return c, nil
+	}
+
+	// ... credentials may be read from a remote store, so clients of other tenants are not blocked meanwhile
+	credentials, err := p.source.Credentials(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// ... the client may have been created by a concurrent call in the meantime
+	if c, ok := p.clients[tenant]; ok {
+		return c, nil
+	}
+
+	opts := append([]Option{WithMetricsLabels(map[string]string{"tenant": tenant})}, p.opts...)
+	opts = append(opts, WithRateLimiter(p.rateLimiter(tenant)))
+	if p.tenantOptions != nil {
+		opts = append(opts, p.tenantOptions(tenant)...)
+	}
+
+	c = NewClient(clientConfig{Url: credentials.Url, User: credentials.User, Key: credentials.Key}, p.logger, opts...)
+	p.clients[tenant] = c
+
+	return c, nil
+}
+
+// Remove drops the client of the tenant, the next call to Client reads its credentials again.
+func (p *ClientPool) Remove(tenant string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.clients, tenant)
+}
+
+// TenantResult is the outcome of an operation run for a tenant.
+type TenantResult struct {
+	Tenant string
+	Value  interface{}
+	Err    error
+}
+
+// TenantFunc is an operation run with the client of a tenant, e.g. a resource call.
+type TenantFunc func(ctx context.Context, tenant string, c Client) (interface{}, error)
+
+// ForTenants runs fn concurrently for the tenants and returns their results in the order of tenants.
+// Tenants whose client can not be created get the error in their result without running fn.
+func (p *ClientPool) ForTenants(ctx context.Context, tenants []string, fn TenantFunc) []TenantResult {
+	operations := make([]Operation, len(tenants))
+	for i, tenant := range tenants {
+		tenant := tenant
+
+		operations[i] = Operation{
+			Key: tenant,
+			Run: func(ctx context.Context) (interface{}, error) {
+				c, err := p.Client(tenant)
+				if err != nil {
+					return nil, err
+				}
+
+				return fn(ctx, tenant, c)
+			},
+		}
+	}
+
+	batchResults := NewBatch(p.maxConcurrency).Run(ctx, operations)
+
+	results := make([]TenantResult, len(batchResults))
+	for i, result := range batchResults {
+		results[i] = TenantResult{Tenant: result.Key, Value: result.Value, Err: result.Err}
+	}
+
+	return results
+}
+
+// ForAllTenants runs fn for all tenants of the CredentialSource, see ForTenants.
+func (p *ClientPool) ForAllTenants(ctx context.Context, fn TenantFunc) ([]TenantResult, error) {
+	tenants, err := p.source.Tenants()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.ForTenants(ctx, tenants, fn), nil
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingCredentials struct {
+	StaticCredentials
+	lookups int32
+}
+
+func (cc *countingCredentials) Credentials(tenant string) (TenantCredentials, error) {
+	atomic.AddInt32(&cc.lookups, 1)
+
+	return cc.StaticCredentials.Credentials(tenant)
+}
+
+func Test_Client_Pool_Runs_Operation_For_All_Tenants(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "` + r.URL.Query().Get(fieldUserId) + `", "RequestAction": "GetBrands", "ResponseType": "", "Timestamp": ""}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	credentials := &countingCredentials{StaticCredentials: StaticCredentials{
+		"id": {Url: server.URL, User: "id@sellercenter.net", Key: "1"},
+		"my": {Url: server.URL, User: "my@sellercenter.net", Key: "2"},
+	}}
+
+	var rateLimiters []string
+	collector := &recordingCollector{}
+
+	pool := NewClientPool(credentials, nil,
+		WithClientOptions(WithMetricsCollector(collector)),
+		WithTenantRateLimiter(func(tenant string) *RateLimiter {
+			rateLimiters = append(rateLimiters, tenant)
+			return NewRateLimiter(RateLimit{Rate: 100, Burst: 10}, RateLimitWait)
+		}),
+	)
+
+	getBrands := func(ctx context.Context, tenant string, c Client) (interface{}, error) {
+		response, err := CallContext(ctx, c, NewGenericRequest("GetBrands", MethodGET))
+		if err != nil {
+			return nil, err
+		}
+
+		return response.GetHeadObject().(headSuccessResponse).RequestId, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		results, err := pool.ForAllTenants(context.Background(), getBrands)
+		if err != nil {
+			t.Fatalf("unexpected error `%v`.", err)
+		}
+
+		expected := []TenantResult{
+			{Tenant: "id", Value: "id@sellercenter.net"},
+			{Tenant: "my", Value: "my@sellercenter.net"},
+		}
+
+		if !reflect.DeepEqual(results, expected) {
+			t.Fatalf("expected `%+v`, actual `%+v`.", expected, results)
+		}
+	}
+
+	if lookups := atomic.LoadInt32(&credentials.lookups); lookups != 2 {
+		t.Fatalf("expected clients to be created once per tenant, actual %d credential lookups.", lookups)
+	}
+
+	if len(rateLimiters) != 2 {
+		t.Fatalf("expected one rate limiter per tenant, actual `%v`.", rateLimiters)
+	}
+
+	tenants := map[string]int{}
+	for _, call := range collector.calls {
+		tenants[call.Labels["tenant"]]++
+	}
+
+	if !reflect.DeepEqual(tenants, map[string]int{"id": 2, "my": 2}) {
+		t.Fatalf("expected calls labeled by tenant, actual `%v`.", tenants)
+	}
+}
+
+func Test_Client_Pool_Reports_Unknown_Tenants(t *testing.T) {
+	pool := NewClientPool(StaticCredentials{}, nil)
+
+	results := pool.ForTenants(context.Background(), []string{"sg"}, func(ctx context.Context, tenant string, c Client) (interface{}, error) {
+		t.Fatal("expected operation not to run for unknown tenant.")
+		return nil, nil
+	})
+
+	if len(results) != 1 || !errors.Is(results[0].Err, ErrUnknownTenant) {
+		t.Fatalf("expected ErrUnknownTenant, actual `%+v`.", results)
+	}
+}
+
+func Test_Client_Pool_Limits_Concurrency(t *testing.T) {
+	credentials := StaticCredentials{}
+	tenants := []string{"a", "b", "c", "d"}
+	for _, tenant := range tenants {
+		credentials[tenant] = TenantCredentials{Url: "https://sellercenter-api.example.com/", User: tenant + "@sellercenter.net", Key: tenant}
+	}
+
+	pool := NewClientPool(credentials, nil, WithMaxConcurrency(2))
+
+	var running, maxRunning int32
+	pool.ForTenants(context.Background(), tenants, func(ctx context.Context, tenant string, c Client) (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		return nil, nil
+	})
+
+	if maxRunning != 2 {
+		t.Fatalf("expected at most 2 tenants at the same time, actual %d.", maxRunning)
+	}
+}
+
+type blockingCredentials struct {
+	StaticCredentials
+	blocked string
+	release chan struct{}
+}
+
+func (bc *blockingCredentials) Credentials(tenant string) (TenantCredentials, error) {
+	if tenant == bc.blocked {
+		<-bc.release
+	}
+
+	return bc.StaticCredentials.Credentials(tenant)
+}
+
+func Test_Client_Pool_Does_Not_Block_Tenants_Behind_A_Slow_Credential_Lookup(t *testing.T) {
+	credentials := &blockingCredentials{
+		StaticCredentials: StaticCredentials{
+			"id": {Url: "https://sellercenter-api.example.com/", User: "id@sellercenter.net", Key: "1"},
+			"my": {Url: "https://sellercenter-api.example.com/", User: "my@sellercenter.net", Key: "2"},
+		},
+		blocked: "id",
+		release: make(chan struct{}),
+	}
+
+	pool := NewClientPool(credentials, nil)
+
+	done := make(chan error)
+	go func() {
+		_, err := pool.Client("id")
+		done <- err
+	}()
+
+	created := make(chan error)
+	go func() {
+		_, err := pool.Client("my")
+		created <- err
+	}()
+
+	select {
+	case err := <-created:
+		if err != nil {
+			t.Fatalf("unexpected error `%v`.", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the client of a tenant not to wait for the credentials of another tenant.")
+	}
+
+	close(credentials.release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+}
+
+func Test_Client_Pool_Gives_Every_Tenant_Its_Own_Rate_Limiter(t *testing.T) {
+	credentials := StaticCredentials{
+		"id": {Url: "https://sellercenter-api.example.com/", User: "id@sellercenter.net", Key: "1"},
+		"my": {Url: "https://sellercenter-api.example.com/", User: "my@sellercenter.net", Key: "2"},
+	}
+
+	shared := NewRateLimiter(RateLimit{Rate: 1}, RateLimitWait)
+	pool := NewClientPool(credentials, nil, WithClientOptions(WithRateLimiter(shared)))
+
+	limiters := map[*RateLimiter]bool{}
+	for _, tenant := range []string{"id", "my"} {
+		c, err := pool.Client(tenant)
+		if err != nil {
+			t.Fatalf("unexpected error `%v`.", err)
+		}
+
+		limiter := c.(*client).rateLimiter
+		if limiter == nil || limiter == shared {
+			t.Fatalf("expected a rate limiter of the tenant `%s`, actual `%v`.", tenant, limiter)
+		}
+
+		limiters[limiter] = true
+	}
+
+	if len(limiters) != 2 {
+		t.Fatal("expected tenants not to share a rate limiter.")
+	}
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Errors
+var (
+	ErrRateLimited = errors.New("client side rate limit exceeded")
+)
+
+// RateLimit allows Rate requests per second in the long run and bursts of up to Burst requests.
+// A Rate of zero or less disables limiting.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type RateLimitMode int
+
+const (
+	// RateLimitWait blocks a call until the limiter allows it or its context is done.
+	RateLimitWait RateLimitMode = iota
+	// RateLimitFailFast rejects a call with ErrRateLimited when no token is available.
+	RateLimitFailFast
+)
+
+// RateLimiter is a token bucket limiter keyed by Seller Center action. It is safe for concurrent use,
+// so a single limiter can be shared by every resource built on the same client.
+type RateLimiter struct {
+	mode         RateLimitMode
+	defaultLimit RateLimit
+
+	mu           sync.Mutex
+	actionLimits map[string]RateLimit
+	buckets      map[string]*tokenBucket
+	now          func() time.Time
+}
+
+func NewRateLimiter(defaultLimit RateLimit, mode RateLimitMode) *RateLimiter {
+	return &RateLimiter{
+		mode:         mode,
+		defaultLimit: defaultLimit,
+		actionLimits: map[string]RateLimit{},
+		buckets:      map[string]*tokenBucket{},
+		now:          time.Now,
+	}
+}
+
+// SetActionLimit overrides the default limit for a single action, e.g. `GetOrders` or `ProductUpdate`.
+func (rl *RateLimiter) SetActionLimit(action string, limit RateLimit) *RateLimiter {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.actionLimits[action] = limit
+	delete(rl.buckets, action)
+
+	return rl
+}
+
+// Wait takes a token for the action, blocking or failing according to the limiter mode.
+func (rl *RateLimiter) Wait(ctx context.Context, action string) error {
+	rl.mu.Lock()
+
+	bucket := rl.bucket(action)
+	if bucket == nil {
+		rl.mu.Unlock()
+		return nil
+	}
+
+	now := rl.now()
+	bucket.refill(now)
+
+	if rl.mode == RateLimitFailFast {
+		defer rl.mu.Unlock()
+
+		if bucket.tokens < 1 {
+			return ErrRateLimited
+		}
+
+		bucket.tokens--
+		return nil
+	}
+
+	// ... reserve the token now and wait until it has been refilled
+	bucket.tokens--
+	delay := bucket.delay()
+	rl.mu.Unlock()
+
+	if err := sleepContext(ctx, delay); err != nil {
+		rl.mu.Lock()
+		bucket.tokens++
+		rl.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+func (rl *RateLimiter) bucket(action string) *tokenBucket {
+	if bucket, ok := rl.buckets[action]; ok {
+		return bucket
+	}
+
+	limit, ok := rl.actionLimits[action]
+	if !ok {
+		limit = rl.defaultLimit
+	}
+
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	burst := limit.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	bucket := &tokenBucket{
+		rate:     limit.Rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastSeen: rl.now(),
+	}
+	rl.buckets[action] = bucket
+
+	return bucket
+}
+
+type tokenBucket struct {
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastSeen time.Time
+}
+
+func (tb *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(tb.lastSeen)
+	if elapsed <= 0 {
+		return
+	}
+
+	tb.tokens += elapsed.Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+
+	tb.lastSeen = now
+}
+
+func (tb *tokenBucket) delay() time.Duration {
+	if tb.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeNow struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeNow) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+func (f *fakeNow) Add(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+}
+
+func createRateLimiter(limit RateLimit, mode RateLimitMode) (*RateLimiter, *fakeNow) {
+	now := &fakeNow{now: time.Date(2018, 7, 6, 15, 37, 57, 0, time.UTC)}
+
+	limiter := NewRateLimiter(limit, mode)
+	limiter.now = now.Now
+
+	return limiter, now
+}
+
+func Test_Rate_Limiter_Fails_Fast_When_Burst_Is_Used(t *testing.T) {
+	limiter, now := createRateLimiter(RateLimit{Rate: 1, Burst: 2}, RateLimitFailFast)
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(context.Background(), "GetOrders"); err != nil {
+			t.Fatalf("expected call %d to be allowed, actual `%s`.", i, err)
+		}
+	}
+
+	if err := limiter.Wait(context.Background(), "GetOrders"); err != ErrRateLimited {
+		t.Fatalf("expected ErrRateLimited, actual `%v`.", err)
+	}
+
+	now.Add(time.Second)
+
+	if err := limiter.Wait(context.Background(), "GetOrders"); err != nil {
+		t.Fatalf("expected call to be allowed after refill, actual `%s`.", err)
+	}
+}
+
+func Test_Rate_Limiter_Keeps_Buckets_Per_Action(t *testing.T) {
+	limiter, _ := createRateLimiter(RateLimit{Rate: 1, Burst: 1}, RateLimitFailFast)
+	limiter.SetActionLimit("FeedStatus", RateLimit{Rate: 0})
+
+	if err := limiter.Wait(context.Background(), "GetOrders"); err != nil {
+		t.Fatalf("expected GetOrders to be allowed, actual `%s`.", err)
+	}
+
+	if err := limiter.Wait(context.Background(), "ProductUpdate"); err != nil {
+		t.Fatalf("expected ProductUpdate to have its own bucket, actual `%s`.", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := limiter.Wait(context.Background(), "FeedStatus"); err != nil {
+			t.Fatalf("expected FeedStatus not to be limited, actual `%s`.", err)
+		}
+	}
+}
+
+func Test_Rate_Limiter_Waits_For_Token(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{Rate: 20, Burst: 1}, RateLimitWait)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background(), "GetProducts"); err != nil {
+			t.Fatalf("expected call %d to be allowed, actual `%s`.", i, err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected calls to be spread over at least 100ms, actual %s.", elapsed)
+	}
+}
+
+func Test_Rate_Limiter_Wait_Returns_Context_Error(t *testing.T) {
+	limiter, _ := createRateLimiter(RateLimit{Rate: 0.001, Burst: 1}, RateLimitWait)
+
+	if err := limiter.Wait(context.Background(), "GetProducts"); err != nil {
+		t.Fatalf("expected first call to be allowed, actual `%s`.", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, "GetProducts"); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, actual `%v`.", err)
+	}
+}
+
+func Test_Rate_Limiter_Is_Safe_For_Concurrent_Use(t *testing.T) {
+	limiter, _ := createRateLimiter(RateLimit{Rate: 1, Burst: 50}, RateLimitFailFast)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if limiter.Wait(context.Background(), "GetOrders") == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if allowed != 50 {
+		t.Fatalf("expected 50 calls to be allowed, actual %d.", allowed)
+	}
+}
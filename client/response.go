@@ -3,9 +3,13 @@ package client
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/buger/jsonparser"
+	"github.com/clbanning/mxj"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 )
 
 type Response interface {
@@ -47,11 +51,15 @@ type headSuccessResponse struct {
 type ErrorResponse struct {
 	HeadObject HeadErrorResponse `json:"Head"`
 	Head       []byte
+	Body       []byte `json:"-"`
 }
 
 type HeadErrorResponse struct {
-	ErrorCode    string `json:"ErrorCode"`
-	ErrorMessage string `json:"ErrorMessage"`
+	RequestAction string `json:"RequestAction"`
+	ErrorType     string `json:"ErrorType"`
+	ErrorCode     string `json:"ErrorCode"`
+	ErrorMessage  string `json:"ErrorMessage"`
+	Timestamp     string `json:"Timestamp"`
 }
 
 func (er ErrorResponse) IsError() bool {
@@ -67,14 +75,70 @@ func (er ErrorResponse) GetHead() []byte {
 }
 
 func (er ErrorResponse) GetBody() []byte {
-	return nil
+	return er.Body
 }
 
 // Errors
 var (
 	NoHttp200ResponseError = errors.New("unexpected response")
+	ErrResponseTooLarge    = errors.New("response exceeds the maximum size")
 )
 
+const maxHttpErrorBodySize = 4096
+
+// HTTPError is returned for responses with a status other than 200 that do not carry an ErrorResponse.
+// Body is an excerpt of at most 4KB. It wraps NoHttp200ResponseError, so errors.Is keeps working.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+}
+
+func newHttpError(response http.Response, body []byte) *HTTPError {
+	header := response.Header.Clone()
+	header.Del("Set-Cookie")
+
+	if len(body) > maxHttpErrorBodySize {
+		body = body[:maxHttpErrorBodySize]
+	}
+
+	return &HTTPError{
+		StatusCode: response.StatusCode,
+		Status:     response.Status,
+		Header:     header,
+		Body:       body,
+	}
+}
+
+func (e *HTTPError) Error() string {
+	status := e.Status
+	if status == "" {
+		status = strconv.Itoa(e.StatusCode)
+	}
+
+	return fmt.Sprintf("%s: http %s", NoHttp200ResponseError, status)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return NoHttp200ResponseError
+}
+
+// Is reports whether the target is the HTTPStatus of the response.
+func (e *HTTPError) Is(target error) bool {
+	status, ok := target.(HTTPStatus)
+
+	return ok && int(status) == e.StatusCode
+}
+
+// HTTPStatus matches HTTPErrors of responses with that status code with errors.Is, e.g.
+// errors.Is(err, HTTPStatus(http.StatusTooManyRequests)).
+type HTTPStatus int
+
+func (s HTTPStatus) Error() string {
+	return fmt.Sprintf("%s: http %d %s", NoHttp200ResponseError, int(s), http.StatusText(int(s)))
+}
+
 type ResponseBuilder interface {
 	BuildResponse(response http.Response) (Response, error)
 }
@@ -84,21 +148,88 @@ func NewResponseBuilder() responseBuilder {
 }
 
 type responseBuilder struct {
+	// maxSize limits the size of the response body in bytes, zero means no limit.
+	maxSize int64
 }
 
 func (rb responseBuilder) BuildResponse(response http.Response) (Response, error) {
-	// ... check if http code 200
+	responseBodyBytes, err := readResponseBody(response, rb.maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// ... check if http code 200, Seller Center may still send an ErrorResponse
 	if response.StatusCode != http.StatusOK {
-		return nil, NoHttp200ResponseError
+		if errorResponse, err := rb.handleErrorResponse(responseBodyBytes); err == nil {
+			return errorResponse, nil
+		}
+
+		return nil, newHttpError(response, responseBodyBytes)
 	}
 
-	// ... read http response body
+	return rb.buildFromJson(responseBodyBytes)
+}
+
+func readResponseBody(response http.Response, maxSize int64) ([]byte, error) {
 	defer response.Body.Close()
-	responseBodyBytes, err := ioutil.ReadAll(response.Body)
+
+	// ... error envelopes of unexpected responses may carry long ErrorDetail lists, so they are read up to maxSize, too
+	body, err := limitResponseBody(response, maxSize)
 	if err != nil {
 		return nil, err
 	}
 
+	// ... read http response body
+	return ioutil.ReadAll(body)
+}
+
+// limitResponseBody returns the body of the response, failing with ErrResponseTooLarge as soon as more than maxSize
+// bytes are announced or read. A maxSize of zero or less does not limit the body.
+func limitResponseBody(response http.Response, maxSize int64) (io.Reader, error) {
+	if maxSize <= 0 {
+		return response.Body, nil
+	}
+
+	if response.ContentLength > maxSize {
+		return nil, responseTooLargeError(maxSize)
+	}
+
+	return &sizeLimitedReader{reader: response.Body, remaining: maxSize, maxSize: maxSize}, nil
+}
+
+func responseTooLargeError(maxSize int64) error {
+	return fmt.Errorf("%w: limit of %d bytes", ErrResponseTooLarge, maxSize)
+}
+
+type sizeLimitedReader struct {
+	reader    io.Reader
+	remaining int64
+	maxSize   int64
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		// ... probe for one more byte to tell a body of exactly the limit from a larger one
+		var probe [1]byte
+		n, err := r.reader.Read(probe[:])
+		if n > 0 {
+			return 0, responseTooLargeError(r.maxSize)
+		}
+
+		return 0, err
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+
+	return n, err
+}
+
+func (rb responseBuilder) buildFromJson(responseBodyBytes []byte) (Response, error) {
 	// ... handle error response
 	errorResponse, err := rb.handleErrorResponse(responseBodyBytes)
 	if err == nil {
@@ -135,6 +266,12 @@ func (rb responseBuilder) handleErrorResponse(responseBodyBytes []byte) (Respons
 
 	errorResponse.Head = errorResponseDataHead
 
+	// ... Body holds error details, e.g. ErrorDetail per SellerSku, and is an empty string otherwise
+	errorResponseDataBody, dataType, _, err := jsonparser.Get(responseBodyBytes, "ErrorResponse", "Body")
+	if err == nil && dataType == jsonparser.Object {
+		errorResponse.Body = errorResponseDataBody
+	}
+
 	return errorResponse, nil
 }
 
@@ -172,3 +309,48 @@ func (rb responseBuilder) handleSuccessResponse(responseBodyBytes []byte) (Respo
 
 	return successResponse, nil
 }
+
+// NewXmlResponseBuilder builds the same SuccessResponse and ErrorResponse values as the JSON builder from
+// responses in XML format. Head and Body are converted to JSON, so models decode from either format.
+func NewXmlResponseBuilder() xmlResponseBuilder {
+	return xmlResponseBuilder{}
+}
+
+type xmlResponseBuilder struct {
+	jsonResponseBuilder responseBuilder
+}
+
+func (xb xmlResponseBuilder) BuildResponse(response http.Response) (Response, error) {
+	responseBodyBytes, err := readResponseBody(response, xb.jsonResponseBuilder.maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	responseJson, err := xmlToJson(responseBodyBytes)
+
+	// ... check if http code 200, Seller Center may still send an ErrorResponse
+	if response.StatusCode != http.StatusOK {
+		if err == nil {
+			if errorResponse, err := xb.jsonResponseBuilder.handleErrorResponse(responseJson); err == nil {
+				return errorResponse, nil
+			}
+		}
+
+		return nil, newHttpError(response, responseBodyBytes)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return xb.jsonResponseBuilder.buildFromJson(responseJson)
+}
+
+func xmlToJson(xmlBytes []byte) ([]byte, error) {
+	responseMap, err := mxj.NewMapXml(xmlBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseMap.Json()
+}
@@ -1,8 +1,12 @@
 package client
 
 import (
+	"encoding/json"
+	"errors"
+	"github.com/GFG/seller-center-sdk-go/model"
 	"github.com/buger/jsonparser"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -158,7 +162,7 @@ func Test_Build_Response_With_Http_400(t *testing.T) {
 
 	response, err := responseBuilder.BuildResponse(httpResponse)
 
-	if err != NoHttp200ResponseError {
+	if !errors.Is(err, NoHttp200ResponseError) {
 		t.Fatalf("expected to fail to build response, but actual `%s`.", err)
 	}
 
@@ -259,3 +263,303 @@ func Test_Build_Response_With_Incomplete_Body2(t *testing.T) {
 		t.Fatalf("expected response to be nil, actual `%s`.", response)
 	}
 }
+
+func Test_Can_Build_Success_Response_From_Xml(t *testing.T) {
+
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<SuccessResponse>
+  <Head>
+    <RequestId>789</RequestId>
+    <RequestAction>GetProducts</RequestAction>
+    <ResponseType>Products</ResponseType>
+    <Timestamp>2018-07-06T15:37:57+0200</Timestamp>
+  </Head>
+  <Body>
+    <Products>
+      <Product>
+        <SellerSku>sku-1</SellerSku>
+        <Name><![CDATA[Shirt & Tie]]></Name>
+        <Quantity>3</Quantity>
+      </Product>
+    </Products>
+  </Body>
+</SuccessResponse>`
+
+	bodyReader := &MyReadCloser{strings.NewReader(body)}
+
+	httpResponse := http.Response{
+		StatusCode: http.StatusOK,
+		Body:       bodyReader,
+	}
+
+	response, err := NewXmlResponseBuilder().BuildResponse(httpResponse)
+
+	if err != nil {
+		t.Fatalf("can not build response. expected error to be nil, actual `%s`.", err)
+	}
+
+	if response.IsError() == true {
+		t.Fatal("can not build response. expected get SuccessResponse.")
+	}
+
+	expectedHead := headSuccessResponse{
+		RequestId:     "789",
+		RequestAction: "GetProducts",
+		ResponseType:  "Products",
+		Timestamp:     "2018-07-06T15:37:57+0200",
+	}
+
+	if response.GetHeadObject() != expectedHead {
+		t.Fatalf("can not build response. expected head `%v`, actual `%v`.", expectedHead, response.GetHeadObject())
+	}
+
+	var products model.Products
+	if err := json.Unmarshal(response.GetBody(), &products); err != nil {
+		t.Fatalf("can not decode products from xml response. `%s`", err)
+	}
+
+	if len(products.Products) != 1 || products.Products[0].SellerSku != "sku-1" ||
+		products.Products[0].Name != "Shirt & Tie" || products.Products[0].Quantity != 3 {
+		t.Fatalf("can not decode products from xml response. actual `%v`.", products)
+	}
+}
+
+func Test_Can_Build_Error_Response_From_Xml(t *testing.T) {
+
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<ErrorResponse>
+  <Head>
+    <RequestAction>CreateWebhook</RequestAction>
+    <ErrorType>Sender</ErrorType>
+    <ErrorCode>98</ErrorCode>
+    <ErrorMessage>E098: Invalid Webhook Callback Url</ErrorMessage>
+  </Head>
+  <Body/>
+</ErrorResponse>`
+
+	bodyReader := &MyReadCloser{strings.NewReader(body)}
+
+	httpResponse := http.Response{
+		StatusCode: http.StatusOK,
+		Body:       bodyReader,
+	}
+
+	response, err := NewXmlResponseBuilder().BuildResponse(httpResponse)
+
+	if err != nil {
+		t.Fatalf("can not build error response. expected error to be nil, actual `%s`.", err)
+	}
+
+	if response.IsError() == false {
+		t.Fatal("can not build response. expected get ErrorResponse.")
+	}
+
+	head := response.GetHeadObject().(HeadErrorResponse)
+	if head.ErrorCode != "98" || head.ErrorMessage != "E098: Invalid Webhook Callback Url" {
+		t.Fatalf("can not build response. failed to build errorHeader, actual `%v`.", head)
+	}
+}
+
+func Test_Client_Requests_Xml_Response_Format(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Format") == FormatXML {
+			w.Write([]byte(`<SuccessResponse><Head><RequestId>xml</RequestId></Head><Body/></SuccessResponse>`))
+			return
+		}
+
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "json"}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	xmlClient := createTestClient(server.URL, WithResponseFormat(FormatXML))
+	jsonClient := createTestClient(server.URL)
+
+	jsonRequest := NewGenericRequest("GetProducts", MethodGET)
+	jsonRequest.SetResponseFormat(FormatJSON)
+
+	xmlRequest := NewGenericRequest("GetProducts", MethodGET)
+	xmlRequest.SetResponseFormat(FormatXML)
+
+	testCases := []struct {
+		client   Client
+		request  Request
+		expected string
+	}{
+		{xmlClient, NewGenericRequest("GetProducts", MethodGET), "xml"},
+		{xmlClient, jsonRequest, "json"},
+		{jsonClient, xmlRequest, "xml"},
+	}
+
+	for _, testCase := range testCases {
+		response, err := testCase.client.Call(testCase.request)
+		if err != nil {
+			t.Fatalf("expected error to be nil, actual `%s`.", err)
+		}
+
+		if requestId := response.GetHeadObject().(headSuccessResponse).RequestId; requestId != testCase.expected {
+			t.Fatalf("expected %s response, actual `%s`.", testCase.expected, requestId)
+		}
+	}
+}
+
+func Test_Can_Build_Error_Response_With_Error_Details(t *testing.T) {
+
+	body := `{
+  "ErrorResponse": {
+    "Head": {
+      "RequestAction": "ProductCreate",
+      "ErrorType": "Sender",
+      "ErrorCode": "201",
+      "ErrorMessage": "E201: %s Invalid Feed"
+    },
+    "Body": {
+      "ErrorDetail": {
+        "Field": "Price",
+        "Message": "Field must contain a number",
+        "Value": "abc",
+        "SellerSku": "sku-1"
+      }
+    }
+  }
+}`
+
+	bodyReader := &MyReadCloser{strings.NewReader(body)}
+
+	httpResponse := http.Response{
+		StatusCode: http.StatusOK,
+		Body:       bodyReader,
+	}
+
+	response, err := NewResponseBuilder().BuildResponse(httpResponse)
+
+	if err != nil {
+		t.Fatalf("can not build error response. expected error to be nil, actual `%s`.", err)
+	}
+
+	expectedHead := HeadErrorResponse{
+		RequestAction: "ProductCreate",
+		ErrorType:     "Sender",
+		ErrorCode:     "201",
+		ErrorMessage:  "E201: %s Invalid Feed",
+	}
+
+	if response.GetHeadObject() != expectedHead {
+		t.Fatalf("can not build error response. expected head `%v`, actual `%v`.", expectedHead, response.GetHeadObject())
+	}
+
+	sku, err := jsonparser.GetString(response.GetBody(), "ErrorDetail", "SellerSku")
+	if err != nil || sku != "sku-1" {
+		t.Fatalf("can not build error response. failed to set Body property, actual `%s`.", response.GetBody())
+	}
+}
+
+func Test_Build_Response_With_Http_503_Returns_Http_Error(t *testing.T) {
+
+	body := "<html>" + strings.Repeat("maintenance ", 1000) + "</html>"
+
+	bodyReader := &MyReadCloser{strings.NewReader(body)}
+
+	header := http.Header{}
+	header.Set("Retry-After", "120")
+	header.Set("Set-Cookie", "session=secret")
+
+	httpResponse := http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Status:     "503 Service Unavailable",
+		Header:     header,
+		Body:       bodyReader,
+	}
+
+	response, err := NewResponseBuilder().BuildResponse(httpResponse)
+
+	if response != nil {
+		t.Fatalf("expected response to be nil, actual `%v`.", response)
+	}
+
+	var httpError *HTTPError
+	if !errors.As(err, &httpError) {
+		t.Fatalf("expected HTTPError, actual `%v`.", err)
+	}
+
+	if httpError.StatusCode != http.StatusServiceUnavailable || httpError.Header.Get("Retry-After") != "120" {
+		t.Fatalf("expected HTTPError to carry status and headers, actual `%v`.", httpError)
+	}
+
+	if httpError.Header.Get("Set-Cookie") != "" {
+		t.Fatal("expected HTTPError not to carry cookies.")
+	}
+
+	if len(httpError.Body) != maxHttpErrorBodySize || !strings.HasPrefix(string(httpError.Body), "<html>maintenance") {
+		t.Fatalf("expected HTTPError to carry a bounded body excerpt, actual %d bytes.", len(httpError.Body))
+	}
+
+	if err.Error() != "unexpected response: http 503 Service Unavailable" {
+		t.Fatalf("unexpected error message `%s`.", err)
+	}
+}
+
+func Test_Build_Response_With_Http_400_Parses_Error_Response(t *testing.T) {
+
+	body := `{
+  "ErrorResponse": {
+    "Head": {
+      "RequestAction": "GetOrders",
+      "ErrorType": "Sender",
+      "ErrorCode": "7",
+      "ErrorMessage": "E7: Login failed. Signature mismatch"
+    },
+    "Body": ""
+  }
+}`
+
+	for _, responseBuilder := range []ResponseBuilder{NewResponseBuilder(), NewXmlResponseBuilder()} {
+		responseBody := body
+		if _, ok := responseBuilder.(xmlResponseBuilder); ok {
+			responseBody = `<ErrorResponse><Head><RequestAction>GetOrders</RequestAction><ErrorType>Sender</ErrorType><ErrorCode>7</ErrorCode><ErrorMessage>E7: Login failed. Signature mismatch</ErrorMessage></Head><Body/></ErrorResponse>`
+		}
+
+		httpResponse := http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       &MyReadCloser{strings.NewReader(responseBody)},
+		}
+
+		response, err := responseBuilder.BuildResponse(httpResponse)
+
+		if err != nil {
+			t.Fatalf("expected error to be nil, actual `%s`.", err)
+		}
+
+		if !response.IsError() || response.GetHeadObject().(HeadErrorResponse).ErrorCode != "7" {
+			t.Fatalf("expected ErrorResponse, actual `%v`.", response)
+		}
+	}
+}
+
+func Test_Build_Response_With_Http_400_Parses_Large_Error_Response(t *testing.T) {
+	details := make([]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		details = append(details, `{"Field": "Price", "Message": "Field must contain a number", "Value": "abc", "SellerSku": "sku-`+strings.Repeat("x", 20)+`"}`)
+	}
+
+	body := `{"ErrorResponse": {"Head": {"RequestAction": "ProductCreate", "ErrorType": "Sender", "ErrorCode": "201", "ErrorMessage": "E201: %s Invalid Feed"}, "Body": {"ErrorDetail": [` + strings.Join(details, ",") + `]}}}`
+
+	httpResponse := http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body:       &MyReadCloser{strings.NewReader(body)},
+	}
+
+	response, err := NewResponseBuilder().BuildResponse(httpResponse)
+
+	if err != nil {
+		t.Fatalf("expected error to be nil, actual `%s`.", err)
+	}
+
+	if !response.IsError() || len(response.GetBody()) <= maxHttpErrorBodySize {
+		t.Fatalf("expected ErrorResponse with all error details, actual `%v`.", response)
+	}
+
+	httpResponse.Body = &MyReadCloser{strings.NewReader(body)}
+	if _, err := (responseBuilder{maxSize: 1024}).BuildResponse(httpResponse); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, actual `%v`.", err)
+	}
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 200 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+	defaultRetryJitter    = 0.5
+	defaultMaxRetryAfter  = 30 * time.Second
+	headerRetryAfter      = "Retry-After"
+)
+
+// RetryPolicy decides whether a finished attempt is repeated and how long the client waits before doing so.
+type RetryPolicy interface {
+	ShouldRetry(attempt RetryAttempt) (time.Duration, bool)
+}
+
+// RetryAttempt describes the outcome of a single HTTP round trip. Response is nil when Err is set.
+type RetryAttempt struct {
+	Number   int
+	Action   string
+	Method   string
+	Response *http.Response
+	Err      error
+}
+
+// ExponentialBackoffRetryPolicy retries with exponentially growing, jittered delays and honours Retry-After.
+//
+// GET actions are treated as idempotent and retried on transient network errors and on every 5xx status.
+// POST actions mutate data on Seller Center, so they are only retried when the request never reached it, e.g.
+// on a refused connection or a failed DNS lookup, or when the server clearly rejected the request before
+// processing it (429 and 503), unless they are listed in IdempotentActions.
+type ExponentialBackoffRetryPolicy struct {
+	MaxAttempts       int
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+	Jitter            float64
+	MaxRetryAfter     time.Duration
+	IdempotentActions map[string]bool
+}
+
+func NewExponentialBackoffRetryPolicy() *ExponentialBackoffRetryPolicy {
+	return &ExponentialBackoffRetryPolicy{
+		MaxAttempts:       maxRetries,
+		BaseDelay:         defaultRetryBaseDelay,
+		MaxDelay:          defaultRetryMaxDelay,
+		Jitter:            defaultRetryJitter,
+		MaxRetryAfter:     defaultMaxRetryAfter,
+		IdempotentActions: map[string]bool{},
+	}
+}
+
+func (p *ExponentialBackoffRetryPolicy) ShouldRetry(attempt RetryAttempt) (time.Duration, bool) {
+	if attempt.Number >= p.MaxAttempts || !p.isRetryable(attempt) {
+		return 0, false
+	}
+
+	if delay, ok := retryAfter(attempt.Response); ok {
+		if p.MaxRetryAfter > 0 && delay > p.MaxRetryAfter {
+			delay = p.MaxRetryAfter
+		}
+
+		return delay, true
+	}
+
+	return p.backoff(attempt.Number), true
+}
+
+func (p *ExponentialBackoffRetryPolicy) isRetryable(attempt RetryAttempt) bool {
+	idempotent := attempt.Method == MethodGET || p.IdempotentActions[attempt.Action]
+
+	// ... requests failing to connect never reached Seller Center, so they are retried for POST actions, too
+	if attempt.Err != nil || attempt.Response == nil {
+		return (idempotent || !isAmbiguousError(attempt.Err)) && isTransientError(attempt.Err)
+	}
+
+	switch status := attempt.Response.StatusCode; {
+	case status == http.StatusTooManyRequests, status == http.StatusServiceUnavailable:
+		return true
+	case status >= 500:
+		return idempotent
+	}
+
+	return false
+}
+
+func (p *ExponentialBackoffRetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	jitter := math.Min(math.Max(p.Jitter, 0), 1)
+
+	return time.Duration(delay*(1-jitter) + rand.Float64()*delay*jitter)
+}
+
+// retryAfter reads the Retry-After header, given either in seconds or as an HTTP date.
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+
+	value := response.Header.Get(headerRetryAfter)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+
+		return delay, true
+	}
+
+	return 0, false
+}
+
+func isTransientError(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
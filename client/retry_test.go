@@ -0,0 +1,165 @@
+package client
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func createResponse(statusCode int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{StatusCode: statusCode, Header: header}
+}
+
+func Test_Retry_Policy_Retries_Get_On_Server_Errors(t *testing.T) {
+	policy := NewExponentialBackoffRetryPolicy()
+
+	for _, status := range []int{429, 500, 502, 503, 504} {
+		_, retry := policy.ShouldRetry(RetryAttempt{Number: 1, Action: "GetOrders", Method: MethodGET, Response: createResponse(status, nil)})
+		if !retry {
+			t.Fatalf("expected GET with http %d to be retried.", status)
+		}
+	}
+
+	for _, status := range []int{200, 400, 404} {
+		_, retry := policy.ShouldRetry(RetryAttempt{Number: 1, Action: "GetOrders", Method: MethodGET, Response: createResponse(status, nil)})
+		if retry {
+			t.Fatalf("expected GET with http %d not to be retried.", status)
+		}
+	}
+}
+
+func Test_Retry_Policy_Retries_Post_Only_When_Request_Was_Rejected(t *testing.T) {
+	policy := NewExponentialBackoffRetryPolicy()
+
+	for _, status := range []int{429, 503} {
+		_, retry := policy.ShouldRetry(RetryAttempt{Number: 1, Action: "ProductCreate", Method: MethodPOST, Response: createResponse(status, nil)})
+		if !retry {
+			t.Fatalf("expected POST with http %d to be retried.", status)
+		}
+	}
+
+	for _, status := range []int{500, 502, 504} {
+		_, retry := policy.ShouldRetry(RetryAttempt{Number: 1, Action: "ProductCreate", Method: MethodPOST, Response: createResponse(status, nil)})
+		if retry {
+			t.Fatalf("expected POST with http %d not to be retried.", status)
+		}
+	}
+
+	_, retry := policy.ShouldRetry(RetryAttempt{Number: 1, Action: "SetStatusToCanceled", Method: MethodPOST, Err: syscall.ECONNRESET})
+	if retry {
+		t.Fatal("expected POST with connection reset not to be retried.")
+	}
+
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	_, retry = policy.ShouldRetry(RetryAttempt{Number: 1, Action: "ProductCreate", Method: MethodPOST, Err: &url.Error{Op: "Post", URL: "https://sellercenter.net", Err: dialErr}})
+	if !retry {
+		t.Fatal("expected POST with refused connection to be retried.")
+	}
+
+	policy.IdempotentActions["SetStatusToCanceled"] = true
+
+	_, retry = policy.ShouldRetry(RetryAttempt{Number: 1, Action: "SetStatusToCanceled", Method: MethodPOST, Err: syscall.ECONNRESET})
+	if !retry {
+		t.Fatal("expected POST of idempotent action with connection reset to be retried.")
+	}
+}
+
+func Test_Retry_Policy_Retries_Get_On_Transient_Network_Errors(t *testing.T) {
+	policy := NewExponentialBackoffRetryPolicy()
+
+	_, retry := policy.ShouldRetry(RetryAttempt{Number: 1, Action: "GetOrders", Method: MethodGET, Err: syscall.ECONNRESET})
+	if !retry {
+		t.Fatal("expected GET with connection reset to be retried.")
+	}
+
+	_, retry = policy.ShouldRetry(RetryAttempt{Number: 1, Action: "GetOrders", Method: MethodGET, Err: errors.New("unsupported protocol scheme")})
+	if retry {
+		t.Fatal("expected GET with permanent error not to be retried.")
+	}
+}
+
+func Test_Retry_Policy_Stops_After_Max_Attempts(t *testing.T) {
+	policy := NewExponentialBackoffRetryPolicy()
+
+	_, retry := policy.ShouldRetry(RetryAttempt{Number: maxRetries, Action: "GetOrders", Method: MethodGET, Response: createResponse(503, nil)})
+	if retry {
+		t.Fatal("expected no retry after max attempts.")
+	}
+}
+
+func Test_Retry_Policy_Honours_Retry_After(t *testing.T) {
+	policy := NewExponentialBackoffRetryPolicy()
+
+	header := http.Header{}
+	header.Set("Retry-After", "3")
+
+	delay, retry := policy.ShouldRetry(RetryAttempt{Number: 1, Action: "GetOrders", Method: MethodGET, Response: createResponse(429, header)})
+	if !retry || delay != 3*time.Second {
+		t.Fatalf("expected retry after 3s, actual retry: %t, delay: %s.", retry, delay)
+	}
+
+	header.Set("Retry-After", "3600")
+
+	delay, _ = policy.ShouldRetry(RetryAttempt{Number: 1, Action: "GetOrders", Method: MethodGET, Response: createResponse(429, header)})
+	if delay != policy.MaxRetryAfter {
+		t.Fatalf("expected Retry-After to be capped at %s, actual %s.", policy.MaxRetryAfter, delay)
+	}
+}
+
+func Test_Retry_Policy_Backoff_Grows_Exponentially(t *testing.T) {
+	policy := NewExponentialBackoffRetryPolicy()
+	policy.Jitter = 0
+
+	for attempt, expected := range map[int]time.Duration{1: 200 * time.Millisecond, 2: 400 * time.Millisecond, 3: 800 * time.Millisecond, 10: policy.MaxDelay} {
+		policy.MaxAttempts = attempt + 1
+
+		delay, _ := policy.ShouldRetry(RetryAttempt{Number: attempt, Action: "GetOrders", Method: MethodGET, Response: createResponse(503, nil)})
+		if delay != expected {
+			t.Fatalf("expected delay %s for attempt %d, actual %s.", expected, attempt, delay)
+		}
+	}
+}
+
+func Test_Client_Retries_With_Retry_Policy(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "1"}, "Body": ""}}`))
+	}))
+	defer server.Close()
+
+	policy := NewExponentialBackoffRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	c := createTestClient(server.URL, WithRetryPolicy(policy))
+
+	response, err := c.Call(NewGenericRequest("GetOrders", MethodGET))
+	if err != nil {
+		t.Fatalf("expected error to be nil, actual `%s`.", err)
+	}
+
+	if response.IsError() || atomic.LoadInt32(&calls) != 3 {
+		t.Fatalf("expected success response after 3 calls, actual %d calls.", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+
+	_, err = c.Call(NewGenericRequest("ProductCreate", MethodPOST))
+	if !errors.Is(err, NoHttp200ResponseError) || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("expected POST not to be retried on http 502, actual %d calls, err `%v`.", calls, err)
+	}
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Errors
+var (
+	ErrMissingSignature        = errors.New("request is not signed")
+	ErrSignatureMismatch       = errors.New("request signature mismatch")
+	ErrUnknownUser             = errors.New("unknown request user")
+	ErrInvalidRequestTimestamp = errors.New("invalid request timestamp")
+	ErrRequestExpired          = errors.New("request timestamp expired")
+)
+
+// CanonicalQuery returns the string a request signature is computed over: all params but Signature, sorted by
+// key and url encoded with spaces as %20.
+func CanonicalQuery(params url.Values) string {
+	signed := make(url.Values, len(params))
+	for key, values := range params {
+		if key != fieldSignature {
+			signed[key] = values
+		}
+	}
+
+	return encodeQuery(signed)
+}
+
+func encodeQuery(params url.Values) string {
+	return strings.Replace(params.Encode(), "+", "%20", -1)
+}
+
+// Signer signs request params for a Seller Center account the same way the client does.
+type Signer struct {
+	user string
+	key  string
+}
+
+func NewSigner(user string, key string) Signer {
+	return Signer{user: user, key: key}
+}
+
+// Signature returns the HMAC-SHA256 signature of params, a Signature param among them is ignored.
+func (s Signer) Signature(params url.Values) string {
+	h := hmac.New(sha256.New, []byte(s.key))
+	h.Write([]byte(CanonicalQuery(params)))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Sign sets UserID, Timestamp and Signature of params for the account of the signer, replacing existing
+// values, e.g. to re-sign a request received for another account.
+func (s Signer) Sign(params url.Values, timestamp time.Time) {
+	params.Set(fieldUserId, s.user)
+	params.Set(fieldTimestamp, timestamp.Format(time.RFC3339))
+	params.Set(fieldSignature, s.Signature(params))
+}
+
+// Verify checks the Signature param of params against the key of the signer. It neither checks the UserID
+// nor the Timestamp, see VerifyRequest.
+func (s Signer) Verify(params url.Values) error {
+	signature := params.Get(fieldSignature)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.Signature(params))) {
+		return ErrSignatureMismatch
+	}
+
+	return nil
+}
+
+// VerifyRequest checks the signature of request params signed with key and that their Timestamp is at most
+// maxAge away from now. A maxAge of zero skips the timestamp check.
+func VerifyRequest(params url.Values, key string, maxAge time.Duration) error {
+	return verifyRequest(params, key, maxAge, time.Now())
+}
+
+func verifyRequest(params url.Values, key string, maxAge time.Duration, now time.Time) error {
+	if err := NewSigner(params.Get(fieldUserId), key).Verify(params); err != nil {
+		return err
+	}
+
+	if maxAge <= 0 {
+		return nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, params.Get(fieldTimestamp))
+	if err != nil {
+		return ErrInvalidRequestTimestamp
+	}
+
+	if age := now.Sub(timestamp); age > maxAge || age < -maxAge {
+		return ErrRequestExpired
+	}
+
+	return nil
+}
+
+// KeyFunc returns the API key of a user, false if the user is unknown.
+type KeyFunc func(user string) (string, bool)
+
+// SignatureMiddleware passes only requests to next that are signed with the key of their UserID and not older
+// than maxAge. Other requests are answered with 403 and an ErrorResponse envelope.
+func SignatureMiddleware(keys KeyFunc, maxAge time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			params := r.URL.Query()
+
+			err := ErrUnknownUser
+			if key, ok := keys(params.Get(fieldUserId)); ok {
+				err = VerifyRequest(params, key, maxAge)
+			}
+
+			if err != nil {
+				writeSignatureError(w, params.Get(fieldAction), err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// signatureErrors maps verification errors to the code and message Seller Center answers with.
+var signatureErrors = map[error][2]string{
+	ErrMissingSignature:        {"1", "E001: Parameter Signature is mandatory"},
+	ErrSignatureMismatch:       {"7", "E007: Login failed. Signature mismatching"},
+	ErrUnknownUser:             {"9", "E009: Access Denied"},
+	ErrInvalidRequestTimestamp: {"4", "E004: Invalid Timestamp format"},
+	ErrRequestExpired:          {"3", "E003: Timestamp has expired"},
+}
+
+func writeSignatureError(w http.ResponseWriter, action string, err error) {
+	codeAndMessage := signatureErrors[err]
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ErrorResponse": map[string]interface{}{
+			"Head": HeadErrorResponse{
+				RequestAction: action,
+				ErrorType:     "Sender",
+				ErrorCode:     codeAndMessage[0],
+				ErrorMessage:  codeAndMessage[1],
+				Timestamp:     time.Now().Format(ResponseTimestampFormat),
+			},
+			"Body": "",
+		},
+	})
+}
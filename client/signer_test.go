@@ -0,0 +1,113 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_Signer_Signs_Like_The_Url_Builder(t *testing.T) {
+	timestamp, _ := time.Parse(time.RFC3339, "2014-11-12T11:45:26Z")
+
+	params := url.Values{}
+	params.Add("Foo", "bar")
+	params.Add("Whatever", "you want")
+
+	NewSigner("abc@sellercenter.net", "1234567890").Sign(params, timestamp)
+
+	expected := "bb6b9ee192fe31f7e21c2fcc6298feccc777b747beb0a062e7c21072671a5cae"
+	if signature := params.Get(fieldSignature); signature != expected {
+		t.Fatalf("can not sign params. expected: `%s` - actual: `%s`.", expected, signature)
+	}
+
+	if err := NewSigner("", "1234567890").Verify(params); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+}
+
+func Test_Signer_Can_Resign_Params_For_Another_Account(t *testing.T) {
+	params := url.Values{}
+	params.Add(fieldAction, "GetProducts")
+	NewSigner("proxy@sellercenter.net", "proxy-key").Sign(params, time.Now())
+
+	NewSigner("seller@sellercenter.net", "seller-key").Sign(params, time.Now())
+
+	if user := params.Get(fieldUserId); user != "seller@sellercenter.net" {
+		t.Fatalf("expected UserID to be replaced, actual `%s`.", user)
+	}
+
+	if err := VerifyRequest(params, "seller-key", time.Minute); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if err := VerifyRequest(params, "proxy-key", time.Minute); err != ErrSignatureMismatch {
+		t.Fatalf("expected ErrSignatureMismatch, actual `%v`.", err)
+	}
+}
+
+func Test_Verify_Request_Checks_Signature_And_Timestamp(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2020-01-01T12:00:00Z")
+	signer := NewSigner("abc@sellercenter.net", "1234567890")
+
+	signed := func(timestamp time.Time) url.Values {
+		params := url.Values{}
+		params.Add(fieldAction, "GetOrders")
+		signer.Sign(params, timestamp)
+
+		return params
+	}
+
+	invalidTimestamp := url.Values{fieldTimestamp: {"yesterday"}}
+	invalidTimestamp.Set(fieldSignature, signer.Signature(invalidTimestamp))
+
+	tests := []struct {
+		name     string
+		params   url.Values
+		maxAge   time.Duration
+		expected error
+	}{
+		{"fresh", signed(now.Add(-time.Minute)), 5 * time.Minute, nil},
+		{"expired", signed(now.Add(-10 * time.Minute)), 5 * time.Minute, ErrRequestExpired},
+		{"from the future", signed(now.Add(10 * time.Minute)), 5 * time.Minute, ErrRequestExpired},
+		{"expired without max age", signed(now.Add(-10 * time.Minute)), 0, nil},
+		{"unsigned", url.Values{fieldAction: {"GetOrders"}}, 0, ErrMissingSignature},
+		{"invalid timestamp", invalidTimestamp, time.Minute, ErrInvalidRequestTimestamp},
+	}
+
+	for _, test := range tests {
+		if err := verifyRequest(test.params, "1234567890", test.maxAge, now); err != test.expected {
+			t.Fatalf("%s: expected `%v`, actual `%v`.", test.name, test.expected, err)
+		}
+	}
+}
+
+func Test_Signature_Middleware_Rejects_Unauthenticated_Requests(t *testing.T) {
+	keys := func(user string) (string, bool) {
+		return "1234567890", user == "abc@sellercenter.net"
+	}
+
+	handler := SignatureMiddleware(keys, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"SuccessResponse": {"Head": {"RequestId": "", "RequestAction": "GetBrands", "ResponseType": "", "Timestamp": ""}, "Body": ""}}`))
+	}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	response, err := createTestClient(server.URL).Call(NewGenericRequest("GetBrands", MethodGET))
+	if err != nil || response.IsError() {
+		t.Fatalf("expected signed request to pass, actual `%v` `%v`.", response, err)
+	}
+
+	c := NewClient(clientConfig{Url: server.URL, User: "abc@sellercenter.net", Key: "wrong"}, nil)
+
+	response, err = c.Call(NewGenericRequest("GetBrands", MethodGET))
+	if err != nil || !response.IsError() {
+		t.Fatalf("expected ErrorResponse, actual `%v` `%v`.", response, err)
+	}
+
+	if head := response.GetHeadObject().(HeadErrorResponse); head.ErrorCode != "7" || head.RequestAction != "GetBrands" {
+		t.Fatalf("unexpected ErrorResponse head `%+v`.", head)
+	}
+}
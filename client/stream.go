@@ -0,0 +1,342 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ElementFunc is called with the raw JSON of every element of a streamed list. Returning an error stops the stream.
+type ElementFunc func(element json.RawMessage) error
+
+// StreamClient is implemented by clients that decode lists of a response element by element while reading it.
+//
+// StreamContext calls fn for every element found at path inside the Body of the SuccessResponse, e.g.
+// []string{"Products", "Product"}. A single object at path is passed on as the only element. The returned
+// SuccessResponse carries the Head but no Body, error responses are returned as they are.
+type StreamClient interface {
+	StreamContext(ctx context.Context, request Request, path []string, fn ElementFunc) (Response, error)
+}
+
+// Stream streams the elements at path of the response to fn, see StreamClient. Clients without support for
+// streaming are called with CallContext and the elements are decoded from the Body of the response.
+func Stream(ctx context.Context, c Client, request Request, path []string, fn ElementFunc) (Response, error) {
+	if streamClient, ok := c.(StreamClient); ok {
+		return streamClient.StreamContext(ctx, request, path, fn)
+	}
+
+	response, err := CallContext(ctx, c, request)
+	if err != nil || response.IsError() {
+		return response, err
+	}
+
+	if err := decodeElements(json.NewDecoder(bytes.NewReader(response.GetBody())), path, fn); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// StreamContext makes the call through the interceptors, rate limiter and retry policy of the client, but
+// decodes the response while reading it, see StreamClient. Interceptors see a SuccessResponse without Body.
+func (c client) StreamContext(ctx context.Context, request Request, path []string, fn ElementFunc) (Response, error) {
+	return c.handler(contextWithStream(ctx, &stream{path: path, fn: fn}), request)
+}
+
+type stream struct {
+	path []string
+	fn   ElementFunc
+}
+
+type streamContextKey struct{}
+
+func contextWithStream(ctx context.Context, s *stream) context.Context {
+	return context.WithValue(ctx, streamContextKey{}, s)
+}
+
+func streamFromContext(ctx context.Context) *stream {
+	s, _ := ctx.Value(streamContextKey{}).(*stream)
+
+	return s
+}
+
+// streamResponseBuilder decodes JSON responses token by token. XML responses are converted as a whole first.
+type streamResponseBuilder struct {
+	stream  *stream
+	maxSize int64
+	xml     bool
+}
+
+func (sb streamResponseBuilder) BuildResponse(response http.Response) (Response, error) {
+	jsonResponseBuilder := responseBuilder{maxSize: sb.maxSize}
+
+	if sb.xml {
+		resp, err := xmlResponseBuilder{jsonResponseBuilder: jsonResponseBuilder}.BuildResponse(response)
+		if err != nil || resp.IsError() {
+			return resp, err
+		}
+
+		return sb.decodeBufferedBody(resp)
+	}
+
+	// ... unexpected responses are small and handled as usual
+	if response.StatusCode != http.StatusOK {
+		return jsonResponseBuilder.BuildResponse(response)
+	}
+
+	defer response.Body.Close()
+
+	body, err := limitResponseBody(response, sb.maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return sb.decode(json.NewDecoder(body), jsonResponseBuilder)
+}
+
+func (sb streamResponseBuilder) decodeBufferedBody(response Response) (Response, error) {
+	if err := decodeElements(json.NewDecoder(bytes.NewReader(response.GetBody())), sb.stream.path, sb.stream.fn); err != nil {
+		return nil, err
+	}
+
+	successResponse, _ := response.(SuccessResponse)
+	successResponse.Body = nil
+
+	return successResponse, nil
+}
+
+func (sb streamResponseBuilder) decode(decoder *json.Decoder, jsonResponseBuilder responseBuilder) (Response, error) {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+
+	var successResponse *SuccessResponse
+	for decoder.More() {
+		key, err := decodeKey(decoder)
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "ErrorResponse":
+			var errorResponseData json.RawMessage
+			if err := decoder.Decode(&errorResponseData); err != nil {
+				return nil, err
+			}
+
+			return jsonResponseBuilder.handleErrorResponse(append(append([]byte(`{"ErrorResponse":`), errorResponseData...), '}'))
+		case "SuccessResponse":
+			if successResponse, err = sb.decodeSuccessResponse(decoder); err != nil {
+				return nil, err
+			}
+		default:
+			if err := skipValue(decoder); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if successResponse == nil {
+		return nil, errors.New("response contains neither SuccessResponse nor ErrorResponse")
+	}
+
+	return *successResponse, nil
+}
+
+func (sb streamResponseBuilder) decodeSuccessResponse(decoder *json.Decoder) (*SuccessResponse, error) {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+
+	successResponse := &SuccessResponse{}
+	for decoder.More() {
+		key, err := decodeKey(decoder)
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "Head":
+			var head json.RawMessage
+			if err := decoder.Decode(&head); err != nil {
+				return nil, err
+			}
+
+			if err := json.Unmarshal(head, &successResponse.HeadObject); err != nil {
+				return nil, err
+			}
+
+			successResponse.Head = head
+		case "Body":
+			if err := decodeElements(decoder, sb.stream.path, sb.stream.fn); err != nil {
+				return nil, err
+			}
+		default:
+			if err := skipValue(decoder); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return successResponse, expectDelim(decoder, '}')
+}
+
+// decodeElements walks down path from the next value of the decoder and passes the elements found there to fn.
+// Values that do not contain path, e.g. the empty string Seller Center sends for empty lists, have no elements.
+func decodeElements(decoder *json.Decoder, path []string, fn ElementFunc) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, _ := token.(json.Delim)
+	if len(path) == 0 {
+		switch delim {
+		case '[':
+			for decoder.More() {
+				var element json.RawMessage
+				if err := decoder.Decode(&element); err != nil {
+					return err
+				}
+
+				if err := fn(element); err != nil {
+					return err
+				}
+			}
+
+			return expectDelim(decoder, ']')
+		case '{':
+			element, err := decodeObject(decoder)
+			if err != nil {
+				return err
+			}
+
+			return fn(element)
+		default:
+			return nil
+		}
+	}
+
+	if delim != '{' {
+		return skipRest(decoder, delim)
+	}
+
+	for decoder.More() {
+		key, err := decodeKey(decoder)
+		if err != nil {
+			return err
+		}
+
+		if key == path[0] {
+			err = decodeElements(decoder, path[1:], fn)
+		} else {
+			err = skipValue(decoder)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, '}')
+}
+
+// decodeObject decodes the rest of an object whose opening brace has been read already.
+func decodeObject(decoder *json.Decoder) (json.RawMessage, error) {
+	var object bytes.Buffer
+	object.WriteByte('{')
+
+	for decoder.More() {
+		key, err := decodeKey(decoder)
+		if err != nil {
+			return nil, err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if object.Len() > 1 {
+			object.WriteByte(',')
+		}
+
+		encodedKey, _ := json.Marshal(key)
+		object.Write(encodedKey)
+		object.WriteByte(':')
+		object.Write(value)
+	}
+
+	object.WriteByte('}')
+
+	return object.Bytes(), expectDelim(decoder, '}')
+}
+
+func decodeKey(decoder *json.Decoder) (string, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return "", err
+	}
+
+	key, ok := token.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected token %v, expected object key", token)
+	}
+
+	return key, nil
+}
+
+func expectDelim(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("unexpected token %v, expected %v", token, expected)
+	}
+
+	return nil
+}
+
+// skipValue reads the next value of the decoder without keeping it.
+func skipValue(decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, _ := token.(json.Delim)
+
+	return skipRest(decoder, delim)
+}
+
+// skipRest reads the rest of an array or object whose opening delimiter has been read already.
+func skipRest(decoder *json.Decoder, delim json.Delim) error {
+	if delim != '{' && delim != '[' {
+		return nil
+	}
+
+	for depth := 1; depth > 0; {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+
+	return nil
+}
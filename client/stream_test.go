@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const streamedProductsBody = `{"SuccessResponse": {"Body": {"Products": {"Product": [{"SellerSku": "sku-1"}, {"SellerSku": "sku-2"}, {"SellerSku": "sku-3"}]}, "Extra": [{"Nested": [1, 2]}]}, "Head": {"RequestId": "", "RequestAction": "GetProducts", "ResponseType": "Products", "Timestamp": "2018-07-06T15:37:57+0200"}}}`
+
+func newStreamTestClient(body string, opts ...Option) (Client, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+
+	return NewClient(clientConfig{Url: server.URL}, nil, opts...), server.Close
+}
+
+func streamSkus(c Client, path []string) ([]string, Response, error) {
+	var skus []string
+	response, err := Stream(context.Background(), c, NewGenericRequest("GetProducts", MethodGET), path, func(element json.RawMessage) error {
+		var product struct{ SellerSku string }
+		if err := json.Unmarshal(element, &product); err != nil {
+			return err
+		}
+
+		skus = append(skus, product.SellerSku)
+
+		return nil
+	})
+
+	return skus, response, err
+}
+
+func Test_Can_Stream_Elements_Of_Response(t *testing.T) {
+	c, closeServer := newStreamTestClient(streamedProductsBody)
+	defer closeServer()
+
+	skus, response, err := streamSkus(c, []string{"Products", "Product"})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if expected := []string{"sku-1", "sku-2", "sku-3"}; !reflect.DeepEqual(skus, expected) {
+		t.Fatalf("expected: %v - actual: %v", expected, skus)
+	}
+
+	successResponse, ok := response.(SuccessResponse)
+	if !ok || successResponse.HeadObject.RequestAction != "GetProducts" || successResponse.Body != nil {
+		t.Fatalf("expected SuccessResponse with head and without body, actual `%+v`.", response)
+	}
+}
+
+func Test_Can_Stream_Single_Object_And_Empty_Lists(t *testing.T) {
+	bodies := map[string][]string{
+		`{"SuccessResponse": {"Head": {}, "Body": {"Products": {"Product": {"SellerSku": "sku-1", "Images": {"Image": ["a", "b"]}}}}}}`: {"sku-1"},
+		`{"SuccessResponse": {"Head": {}, "Body": {"Products": ""}}}`:                                                                   nil,
+		`{"SuccessResponse": {"Head": {}, "Body": ""}}`:                                                                                 nil,
+	}
+
+	for body, expected := range bodies {
+		c, closeServer := newStreamTestClient(body)
+
+		skus, _, err := streamSkus(c, []string{"Products", "Product"})
+		closeServer()
+
+		if err != nil {
+			t.Fatalf("unexpected error `%v` for `%s`.", err, body)
+		}
+
+		if !reflect.DeepEqual(skus, expected) {
+			t.Fatalf("expected: %v - actual: %v", expected, skus)
+		}
+	}
+}
+
+func Test_Stream_Returns_Error_Response(t *testing.T) {
+	c, closeServer := newStreamTestClient(`{"ErrorResponse": {"Head": {"RequestAction": "GetProducts", "ErrorType": "Sender", "ErrorCode": "9", "ErrorMessage": "E009: Access Denied", "Timestamp": ""}, "Body": ""}}`)
+	defer closeServer()
+
+	_, response, err := streamSkus(c, []string{"Products", "Product"})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	errorResponse, ok := response.(ErrorResponse)
+	if !ok || errorResponse.HeadObject.ErrorCode != "9" {
+		t.Fatalf("expected ErrorResponse with code 9, actual `%+v`.", response)
+	}
+}
+
+func Test_Stream_Stops_On_Callback_Error(t *testing.T) {
+	c, closeServer := newStreamTestClient(streamedProductsBody)
+	defer closeServer()
+
+	stop := errors.New("stop")
+	calls := 0
+	_, err := Stream(context.Background(), c, NewGenericRequest("GetProducts", MethodGET), []string{"Products", "Product"}, func(element json.RawMessage) error {
+		calls++
+
+		return stop
+	})
+
+	if err != stop || calls != 1 {
+		t.Fatalf("expected stop after first element, actual `%v` after %d calls.", err, calls)
+	}
+}
+
+func Test_Stream_Falls_Back_To_Body_Of_Non_Streaming_Clients(t *testing.T) {
+	c := FakeClient{FakeResponse: SuccessResponse{Body: []byte(`{"Products": {"Product": [{"SellerSku": "sku-1"}, {"SellerSku": "sku-2"}]}}`)}}
+
+	skus, _, err := streamSkus(c, []string{"Products", "Product"})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if expected := []string{"sku-1", "sku-2"}; !reflect.DeepEqual(skus, expected) {
+		t.Fatalf("expected: %v - actual: %v", expected, skus)
+	}
+}
+
+func Test_Max_Response_Size_Fails_Large_Responses(t *testing.T) {
+	c, closeServer := newStreamTestClient(streamedProductsBody, WithMaxResponseSize(64))
+	defer closeServer()
+
+	if _, err := c.Call(NewGenericRequest("GetProducts", MethodGET)); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, actual `%v`.", err)
+	}
+
+	if _, _, err := streamSkus(c, []string{"Products", "Product"}); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge for stream, actual `%v`.", err)
+	}
+
+	c, closeLargeServer := newStreamTestClient(streamedProductsBody, WithMaxResponseSize(int64(len(streamedProductsBody))))
+	defer closeLargeServer()
+
+	if _, err := c.Call(NewGenericRequest("GetProducts", MethodGET)); err != nil {
+		t.Fatalf("unexpected error `%v` for response of exactly the maximum size.", err)
+	}
+}
+
+func Test_Max_Response_Size_Applies_To_Unannounced_Lengths(t *testing.T) {
+	body := strings.Repeat(" ", 100) + streamedProductsBody
+	httpResponse := http.Response{StatusCode: http.StatusOK, Body: &MyReadCloser{strings.NewReader(body)}, ContentLength: -1}
+
+	if _, err := (responseBuilder{maxSize: 100}).BuildResponse(httpResponse); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, actual `%v`.", err)
+	}
+}
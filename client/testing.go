@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"log"
 )
 
@@ -14,5 +15,17 @@ func (c FakeClient) GetLogger() *log.Logger {
 }
 
 func (c FakeClient) Call(request Request) (Response, error) {
+	return c.CallContext(context.Background(), request)
+}
+
+func (c FakeClient) CallContext(ctx context.Context, request Request) (Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if collector := callInfoCollectorFromContext(ctx); collector != nil {
+		collector.add(newCallInfo(request, c.FakeResponse, &callStats{attempts: 1}, 0))
+	}
+
 	return c.FakeResponse, c.FakeError
 }
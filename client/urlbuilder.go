@@ -1,11 +1,8 @@
 package client
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"net/url"
-	"strings"
 	"time"
 )
 
@@ -13,79 +10,88 @@ type ClientUrlBuilder interface {
 	BuildUrl(requestParams url.Values) (string, error)
 }
 
+// ContextUrlBuilder builds urls signed with the credentials of a CredentialsProvider, which may be retrieved
+// for every url.
+type ContextUrlBuilder interface {
+	ClientUrlBuilder
+	BuildUrlContext(ctx context.Context, requestParams url.Values) (string, error)
+}
+
+// NewClientUrlBuilder returns a builder of urls signed by a Signer for the user and key of the config. The
+// client itself builds unsigned urls and leaves the signing to its Authenticator.
 func NewClientUrlBuilder(clientConfig clientConfig) ClientUrlBuilder {
+	return newClientUrlBuilder(clientConfig, nil)
+}
+
+// NewCredentialsUrlBuilder returns a builder of urls signed with the credentials of the provider instead of the
+// user and key of the config.
+func NewCredentialsUrlBuilder(clientConfig clientConfig, credentials CredentialsProvider) ContextUrlBuilder {
+	return newClientUrlBuilder(clientConfig, credentials)
+}
+
+func newClientUrlBuilder(clientConfig clientConfig, credentials CredentialsProvider) clientUrlBuilder {
 	return clientUrlBuilder{
 		config: clientConfig,
-		hashHmacRequestSignature: hashHmacRequestSignature{
-			key: clientConfig.Key,
-		},
+		signer: NewSigner(clientConfig.User, clientConfig.Key),
 		datetimeProvider: datetimeProvider{
-			now: time.Now(),
+			clock: SystemClock,
 		},
+		credentials: credentials,
 	}
 }
 
 type clientUrlBuilder struct {
-	config                   clientConfig
-	hashHmacRequestSignature HashHmacRequestSignature
-	datetimeProvider         DatetimeProvider
+	config           clientConfig
+	signer           Signer
+	datetimeProvider DatetimeProvider
+	credentials      CredentialsProvider
 }
 
 func (urlBuilder clientUrlBuilder) BuildUrl(requestParams url.Values) (string, error) {
-	currentUrl, err := urlBuilder.baseUrl()
-	if err != nil {
-		return "", err
+	return urlBuilder.BuildUrlContext(context.Background(), requestParams)
+}
+
+// BuildUrlContext signs the request with the credentials of the CredentialsProvider, if there is one,
+// or else with the user and key of the config.
+func (urlBuilder clientUrlBuilder) BuildUrlContext(ctx context.Context, requestParams url.Values) (string, error) {
+	signer := urlBuilder.signer
+	if urlBuilder.credentials != nil {
+		credentials, err := urlBuilder.credentials.Retrieve(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		signer = NewSigner(credentials.User, credentials.Key)
 	}
 
-	requestParams.Add(fieldUserId, urlBuilder.config.User)
+	requestParams.Add(fieldUserId, signer.user)
 	requestParams.Add(fieldTimestamp, urlBuilder.datetimeProvider.getFormatted())
+	requestParams.Add(fieldSignature, signer.Signature(requestParams))
 
-	signature := urlBuilder.createSignatureForRequest(requestParams)
-
-	requestParams.Add(fieldSignature, signature)
-
-	currentUrl.RawQuery = strings.Replace(requestParams.Encode(), "+", "%20", -1)
-
-	return currentUrl.String(), nil
-}
-
-func (urlBuilder clientUrlBuilder) createSignatureForRequest(params url.Values) string {
-	queryString := strings.Replace(params.Encode(), "+", "%20", -1)
-	return urlBuilder.hashHmacRequestSignature.sign(queryString)
+	return buildRequestUrl(urlBuilder.config.Url, requestParams)
 }
 
-func (urlBuilder clientUrlBuilder) baseUrl() (*url.URL, error) {
-	baseUrl, err := url.ParseRequestURI(urlBuilder.config.Url)
+// buildRequestUrl returns the url of a request with the params, which are neither signed nor changed otherwise.
+func buildRequestUrl(baseUrl string, requestParams url.Values) (string, error) {
+	currentUrl, err := url.ParseRequestURI(baseUrl)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return baseUrl, nil
+	currentUrl.RawQuery = encodeQuery(requestParams)
+
+	return currentUrl.String(), nil
 }
 
 type DatetimeProvider interface {
 	getFormatted() string
 }
 
+// datetimeProvider reads the clock for every signed url.
 type datetimeProvider struct {
-	now time.Time
+	clock Clock
 }
 
 func (d datetimeProvider) getFormatted() string {
-	return d.now.Format(time.RFC3339)
-}
-
-type HashHmacRequestSignature interface {
-	sign(requestAsString string) string
-}
-
-type hashHmacRequestSignature struct {
-	key string
-}
-
-func (hashH hashHmacRequestSignature) sign(requestAsString string) string {
-	key := []byte(hashH.key)
-	h := hmac.New(sha256.New, key)
-	h.Write([]byte(requestAsString))
-	return hex.EncodeToString(h.Sum(nil))
+	return d.clock.Now().Format(time.RFC3339)
 }
@@ -1,12 +1,19 @@
 package client
 
 import (
+	"context"
 	"net/url"
 	"reflect"
 	"testing"
 	"time"
 )
 
+type fixedClock time.Time
+
+func (fc fixedClock) Now() time.Time {
+	return time.Time(fc)
+}
+
 func createUrlBuilder(apiUrl string) ClientUrlBuilder {
 	clientConfig := clientConfig{
 		Url:  apiUrl,
@@ -17,14 +24,9 @@ func createUrlBuilder(apiUrl string) ClientUrlBuilder {
 	str := "2014-11-12T11:45:26.371Z"
 	now, _ := time.Parse(time.RFC3339, str)
 
-	urlBuilder := clientUrlBuilder{
-		config: clientConfig,
-		hashHmacRequestSignature: hashHmacRequestSignature{
-			key: clientConfig.Key,
-		},
-		datetimeProvider: datetimeProvider{
-			now: now,
-		},
+	urlBuilder := newClientUrlBuilder(clientConfig, nil)
+	urlBuilder.datetimeProvider = datetimeProvider{
+		clock: fixedClock(now),
 	}
 
 	return urlBuilder
@@ -68,3 +70,20 @@ func Test_Build_Will_Fail_With_Wrong_Url(t *testing.T) {
 	}
 
 }
+
+func Test_Build_Url_Context_Signs_With_Credentials_Of_Provider(t *testing.T) {
+	config := clientConfig{Url: "https://my-api.sc.net/", User: "abc@sellercenter.net", Key: "1234567890"}
+	urlBuilder := NewCredentialsUrlBuilder(config, NewStaticCredentialsProvider("rotated@sellercenter.net", "0987654321"))
+
+	rawUrl, err := urlBuilder.BuildUrlContext(context.Background(), url.Values{"Action": {"GetBrands"}})
+	if err != nil {
+		t.Fatalf("expected error to be nil, actual `%s`.", err)
+	}
+
+	builtUrl, _ := url.Parse(rawUrl)
+	params := builtUrl.Query()
+
+	if params.Get(fieldUserId) != "rotated@sellercenter.net" || NewSigner("rotated@sellercenter.net", "0987654321").Verify(params) != nil {
+		t.Fatalf("expected url signed with the credentials of the provider, actual `%s`.", rawUrl)
+	}
+}
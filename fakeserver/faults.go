@@ -0,0 +1,252 @@
+package fakeserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// AnyAction schedules faults for requests of every action.
+const AnyAction = "*"
+
+// Fault replaces or alters the response of a request. next sends the request to the real server.
+type Fault func(request *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// ServiceUnavailable answers with 503 without passing the request on.
+func ServiceUnavailable() Fault {
+	return func(request *http.Request, next http.RoundTripper) (*http.Response, error) {
+		closeRequestBody(request)
+
+		return newResponse(request, http.StatusServiceUnavailable, nil, []byte("Service Unavailable")), nil
+	}
+}
+
+// TooManyRequests answers with 429 and a Retry-After header in seconds.
+func TooManyRequests(retryAfter time.Duration) Fault {
+	return func(request *http.Request, next http.RoundTripper) (*http.Response, error) {
+		closeRequestBody(request)
+
+		header := http.Header{}
+		header.Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+
+		return newResponse(request, http.StatusTooManyRequests, header, []byte("Too Many Requests")), nil
+	}
+}
+
+// Delay holds the request back before passing it on, or fails with the error of the request context,
+// e.g. when the client timeout is shorter than the delay.
+func Delay(d time.Duration) Fault {
+	return func(request *http.Request, next http.RoundTripper) (*http.Response, error) {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+
+		return next.RoundTrip(request)
+	}
+}
+
+// TruncatedBody passes the request on and cuts the body of the response in half.
+func TruncatedBody() Fault {
+	return func(request *http.Request, next http.RoundTripper) (*http.Response, error) {
+		response, err := next.RoundTrip(request)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+
+		body, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return newResponse(request, response.StatusCode, response.Header, body[:len(body)/2]), nil
+	}
+}
+
+// InvalidJSON answers with 200 and a body that is not JSON.
+func InvalidJSON() Fault {
+	return func(request *http.Request, next http.RoundTripper) (*http.Response, error) {
+		closeRequestBody(request)
+
+		return newResponse(request, http.StatusOK, nil, []byte(`{"SuccessResponse": {"Head": `)), nil
+	}
+}
+
+// ErrorEnvelope answers with 200 and an ErrorResponse envelope with the given code, e.g. ErrorCodeInvalidSignature.
+func ErrorEnvelope(code string, message string) Fault {
+	return func(request *http.Request, next http.RoundTripper) (*http.Response, error) {
+		closeRequestBody(request)
+
+		body, err := json.Marshal(map[string]interface{}{
+			"ErrorResponse": map[string]interface{}{
+				"Head": map[string]interface{}{
+					"RequestAction": request.URL.Query().Get("Action"),
+					"ErrorType":     "Sender",
+					"ErrorCode":     code,
+					"ErrorMessage":  message,
+				},
+				"Body": "",
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return newResponse(request, http.StatusOK, nil, body), nil
+	}
+}
+
+// ConnectionReset fails the request the way a connection reset by the peer does.
+func ConnectionReset() Fault {
+	return func(request *http.Request, next http.RoundTripper) (*http.Response, error) {
+		closeRequestBody(request)
+
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+}
+
+// LostResponse passes the request on, but fails it like a connection reset before the response arrives,
+// so the request has been processed although the client cannot know.
+func LostResponse() Fault {
+	return func(request *http.Request, next http.RoundTripper) (*http.Response, error) {
+		response, err := next.RoundTrip(request)
+		if err != nil {
+			return nil, err
+		}
+		response.Body.Close()
+
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+}
+
+// closeRequestBody closes the body of a request that is answered without passing it on.
+func closeRequestBody(request *http.Request) {
+	if request.Body != nil {
+		request.Body.Close()
+	}
+}
+
+func newResponse(request *http.Request, statusCode int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}
+}
+
+type probableFault struct {
+	probability float64
+	fault       Fault
+}
+
+// FaultTransport is an http.RoundTripper injecting faults into the requests of an action, either scripted,
+// for the next calls in order, or randomly by probability. Scripted faults take precedence.
+type FaultTransport struct {
+	next http.RoundTripper
+
+	mu            sync.Mutex
+	rand          *rand.Rand
+	scripts       map[string][]Fault
+	probabilities map[string][]probableFault
+	calls         map[string]int
+}
+
+// NewFaultTransport passes requests without fault on to next, http.DefaultTransport if nil. The seed makes
+// the faults injected by probability reproducible.
+func NewFaultTransport(next http.RoundTripper, seed int64) *FaultTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &FaultTransport{
+		next:          next,
+		rand:          rand.New(rand.NewSource(seed)),
+		scripts:       map[string][]Fault{},
+		probabilities: map[string][]probableFault{},
+		calls:         map[string]int{},
+	}
+}
+
+// ScriptAction injects the faults into the next requests of the action, one per request. A nil Fault lets
+// the request pass.
+func (ft *FaultTransport) ScriptAction(action string, faults ...Fault) *FaultTransport {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	ft.scripts[action] = append(ft.scripts[action], faults...)
+
+	return ft
+}
+
+// SetActionProbability injects the fault into requests of the action with the given probability.
+func (ft *FaultTransport) SetActionProbability(action string, probability float64, fault Fault) *FaultTransport {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	ft.probabilities[action] = append(ft.probabilities[action], probableFault{probability, fault})
+
+	return ft
+}
+
+// Calls returns how many requests of the action went through the transport, faulty or not.
+func (ft *FaultTransport) Calls(action string) int {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	return ft.calls[action]
+}
+
+func (ft *FaultTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	fault := ft.nextFault(request.URL.Query().Get("Action"))
+	if fault == nil {
+		return ft.next.RoundTrip(request)
+	}
+
+	return fault(request, ft.next)
+}
+
+func (ft *FaultTransport) nextFault(action string) Fault {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	ft.calls[action]++
+
+	for _, key := range []string{action, AnyAction} {
+		if script := ft.scripts[key]; len(script) > 0 {
+			ft.scripts[key] = script[1:]
+			return script[0]
+		}
+	}
+
+	for _, key := range []string{action, AnyAction} {
+		for _, pf := range ft.probabilities[key] {
+			if ft.rand.Float64() < pf.probability {
+				return pf.fault
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package fakeserver_test
+
+import (
+	"context"
+	"errors"
+	"github.com/GFG/seller-center-sdk-go/client"
+	"github.com/GFG/seller-center-sdk-go/fakeserver"
+	"github.com/GFG/seller-center-sdk-go/model"
+	"github.com/GFG/seller-center-sdk-go/resource"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func newFaultyClient(t *testing.T, apiUrl string, transport *fakeserver.FaultTransport, maxAttempts int, opts ...client.Option) client.Client {
+	retryPolicy := client.NewExponentialBackoffRetryPolicy()
+	retryPolicy.MaxAttempts = maxAttempts
+	retryPolicy.BaseDelay = time.Millisecond
+
+	return newTestClient(t, apiUrl, testKey, append([]client.Option{client.WithTransport(transport), client.WithRetryPolicy(retryPolicy)}, opts...)...)
+}
+
+func Test_Client_Retries_Scripted_Faults(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	transport := fakeserver.NewFaultTransport(nil, 1).
+		ScriptAction("FeedList", fakeserver.ServiceUnavailable(), fakeserver.TooManyRequests(0), fakeserver.ConnectionReset()).
+		ScriptAction("ProductCreate", fakeserver.TooManyRequests(0))
+
+	c := newFaultyClient(t, httpServer.URL, transport, 5)
+
+	if _, err := resource.NewFeed(c).FeedList(); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if calls := transport.Calls("FeedList"); calls != 4 {
+		t.Fatalf("expected 4 FeedList calls, actual %d.", calls)
+	}
+
+	products := resource.NewProduct(c)
+	if _, err := products.ProductCreate([]resource.ProductBuilder{*products.InitProduct().WithSellerSku("sku-1")}); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if calls := transport.Calls("ProductCreate"); calls != 2 {
+		t.Fatalf("expected 2 ProductCreate calls, actual %d.", calls)
+	}
+}
+
+func Test_Client_Does_Not_Retry_Connection_Reset_Of_Post(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	transport := fakeserver.NewFaultTransport(nil, 1).ScriptAction("ProductCreate", fakeserver.ConnectionReset())
+	products := resource.NewProduct(newFaultyClient(t, httpServer.URL, transport, 5))
+
+	_, err := products.ProductCreate([]resource.ProductBuilder{*products.InitProduct().WithSellerSku("sku-1")})
+
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Fatalf("expected ECONNRESET, actual `%v`.", err)
+	}
+
+	if !errors.Is(err, client.ErrAmbiguousOutcome) {
+		t.Fatalf("expected ErrAmbiguousOutcome, actual `%v`.", err)
+	}
+
+	if calls := transport.Calls("ProductCreate"); calls != 1 {
+		t.Fatalf("expected 1 ProductCreate call, actual %d.", calls)
+	}
+}
+
+func Test_Client_Times_Out_On_Slow_Responses(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	transport := fakeserver.NewFaultTransport(nil, 1).ScriptAction("FeedList", fakeserver.Delay(time.Second), fakeserver.Delay(time.Second))
+	feeds := resource.NewFeed(newFaultyClient(t, httpServer.URL, transport, 1, client.WithTimeout(20*time.Millisecond)))
+
+	_, err := feeds.FeedList()
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expected timeout error, actual `%v`.", err)
+	}
+
+	feeds = resource.NewFeed(newFaultyClient(t, httpServer.URL, transport, 2, client.WithTimeout(20*time.Millisecond)))
+	if _, err = feeds.FeedList(); err != nil {
+		t.Fatalf("expected retry after timeout to succeed, actual `%v`.", err)
+	}
+}
+
+func Test_Client_Reports_Broken_Bodies_And_Error_Envelopes(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	transport := fakeserver.NewFaultTransport(nil, 1).ScriptAction("FeedList",
+		fakeserver.TruncatedBody(),
+		fakeserver.InvalidJSON(),
+		fakeserver.ErrorEnvelope(fakeserver.ErrorCodeInvalidSignature, "E007: Login failed. Signature mismatching"),
+	)
+	feeds := resource.NewFeed(newFaultyClient(t, httpServer.URL, transport, 1))
+
+	for i := 0; i < 2; i++ {
+		if _, err := feeds.FeedList(); err == nil {
+			t.Fatalf("expected error for broken body %d.", i)
+		}
+	}
+
+	if _, err := feeds.FeedList(); !errors.Is(err, resource.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, actual `%v`.", err)
+	}
+
+	if _, err := feeds.FeedList(); err != nil {
+		t.Fatalf("expected script to be used up, actual `%v`.", err)
+	}
+}
+
+func Test_Fault_Transport_Injects_Faults_By_Probability(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	transport := fakeserver.NewFaultTransport(nil, 1).
+		SetActionProbability(fakeserver.AnyAction, 1, fakeserver.ServiceUnavailable()).
+		SetActionProbability("FeedList", 0, fakeserver.ConnectionReset())
+	feeds := resource.NewFeed(newFaultyClient(t, httpServer.URL, transport, 3))
+
+	_, err := feeds.FeedList()
+
+	var httpErr *client.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != 503 {
+		t.Fatalf("expected http 503 error, actual `%v`.", err)
+	}
+
+	if calls := transport.Calls("FeedList"); calls != 3 {
+		t.Fatalf("expected 3 FeedList calls, actual %d.", calls)
+	}
+}
+
+func Test_Product_Create_Once_Finds_Feed_Of_Lost_Response(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	transport := fakeserver.NewFaultTransport(nil, 1).ScriptAction("ProductCreate", fakeserver.LostResponse())
+	products := resource.NewProduct(newFaultyClient(t, httpServer.URL, transport, 5))
+
+	feedId, err := products.ProductCreateOnce([]resource.ProductBuilder{*products.InitProduct().WithSellerSku("sku-1")})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if calls := transport.Calls("ProductCreate"); calls != 1 {
+		t.Fatalf("expected 1 ProductCreate call, actual %d.", calls)
+	}
+
+	feed, ok, err := resource.NewFeed(newTestClient(t, httpServer.URL, testKey)).FindFeed("ProductCreate", time.Now().Add(-time.Hour), 1, nil)
+	if err != nil || !ok || feed.Feed != feedId {
+		t.Fatalf("expected feed `%s` of the lost response, actual `%+v` `%v`.", feedId, feed, err)
+	}
+}
+
+func Test_Product_Create_Once_Finds_Feed_Of_Lost_Response_In_Zone_Of_Venue(t *testing.T) {
+	for _, location := range []*time.Location{time.FixedZone("BRT", -3*60*60), time.FixedZone("PHT", 8*60*60)} {
+		server := fakeserver.New(testUser, testKey)
+		server.SetLocation(location)
+		httpServer := server.Start()
+
+		// ... a feed of an earlier sync, created before the request of the lost response was sent
+		server.SetClock(func() time.Time { return time.Now().Add(-2 * time.Hour) })
+		products := resource.NewProduct(newTestClient(t, httpServer.URL, testKey))
+		earlierFeedId, err := products.ProductCreate([]resource.ProductBuilder{*products.InitProduct().WithSellerSku("sku-0")})
+		if err != nil {
+			t.Fatalf("unexpected error `%v`.", err)
+		}
+		server.SetClock(time.Now)
+
+		transport := fakeserver.NewFaultTransport(nil, 1).ScriptAction("ProductCreate", fakeserver.LostResponse())
+		products = resource.NewProduct(newFaultyClient(t, httpServer.URL, transport, 5))
+
+		feedId, err := products.ProductCreateOnce([]resource.ProductBuilder{*products.InitProduct().WithSellerSku("sku-1")})
+		if err != nil {
+			t.Fatalf("unexpected error in %s `%v`.", location, err)
+		}
+
+		if calls := transport.Calls("ProductCreate"); calls != 1 {
+			t.Fatalf("expected 1 ProductCreate call in %s, actual %d.", location, calls)
+		}
+
+		if feed, ok := server.Feed(feedId); !ok || feedId == earlierFeedId {
+			t.Fatalf("expected feed `%s` of the lost response in %s, actual `%+v`.", feedId, location, feed)
+		}
+
+		httpServer.Close()
+	}
+}
+
+func Test_Find_Feed_Matches_Seller_Skus_And_Reports_Ambiguous_Feeds(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	server.SetFeedPolls(0)
+	server.AddProduct(fakeserver.Product{SellerSku: "sku-1"})
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	c := newTestClient(t, httpServer.URL, testKey)
+	products := resource.NewProduct(c)
+	feeds := resource.NewFeed(c)
+	since := time.Now().Add(-time.Minute)
+
+	if _, err := products.ProductUpdate([]resource.ProductBuilder{*products.InitProduct().WithSellerSku("sku-missing")}); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	feedId, err := products.ProductUpdate([]resource.ProductBuilder{*products.InitProduct().WithSellerSku("sku-1")})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	feed, ok, err := feeds.FindFeed("ProductUpdate", since, 1, []string{"sku-1"})
+	if err != nil || !ok || feed.Feed != feedId {
+		t.Fatalf("expected feed `%s` of sku-1, actual `%+v` `%v`.", feedId, feed, err)
+	}
+
+	if _, err := products.ProductUpdate([]resource.ProductBuilder{*products.InitProduct().WithSellerSku("sku-1")}); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if _, ok, err := feeds.FindFeed("ProductUpdate", since, 1, []string{"sku-1"}); ok || !errors.Is(err, resource.ErrAmbiguousFeed) {
+		t.Fatalf("expected ErrAmbiguousFeed, actual `%v` `%v`.", ok, err)
+	}
+}
+
+func Test_Product_Create_Once_Repeats_Request_Without_Feed(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	transport := fakeserver.NewFaultTransport(nil, 1).ScriptAction("ProductCreate", fakeserver.ConnectionReset())
+	products := resource.NewProduct(newFaultyClient(t, httpServer.URL, transport, 5))
+
+	feedId, err := products.ProductCreateOnce([]resource.ProductBuilder{*products.InitProduct().WithSellerSku("sku-1")})
+	if err != nil || feedId == "" {
+		t.Fatalf("unexpected result `%s` `%v`.", feedId, err)
+	}
+
+	if calls := transport.Calls("ProductCreate"); calls != 2 {
+		t.Fatalf("expected 2 ProductCreate calls, actual %d.", calls)
+	}
+}
+
+func Test_Retry_Ambiguous_Checks_Order_Item_Status(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	order := server.AddOrder(fakeserver.Order{Items: []fakeserver.OrderItem{{Sku: "sku-1"}}})
+	orderItemId := order.Items[0].OrderItemId
+
+	transport := fakeserver.NewFaultTransport(nil, 1).ScriptAction("SetStatusToCanceled", fakeserver.LostResponse())
+	orders := resource.NewOrder(newFaultyClient(t, httpServer.URL, transport, 5))
+
+	err := resource.RetryAmbiguous(context.Background(), func(ctx context.Context) error {
+		_, err := orders.SetStatusToCanceledContext(ctx, orderItemId, "Out of stock", "")
+
+		return err
+	}, orders.OrderItemsHaveStatus(order.OrderId, []int{orderItemId}, model.OrderItemStatusCanceled))
+
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if calls := transport.Calls("SetStatusToCanceled"); calls != 1 {
+		t.Fatalf("expected 1 SetStatusToCanceled call, actual %d.", calls)
+	}
+}
@@ -0,0 +1,192 @@
+package fakeserver
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	FeedStatusQueued   = "Queued"
+	FeedStatusFinished = "Finished"
+)
+
+// Feed is the state the server keeps for a feed created by a POST action.
+type Feed struct {
+	Id               string
+	Action           string
+	Status           string
+	CreationDate     time.Time
+	UpdatedDate      time.Time
+	TotalRecords     int
+	ProcessedRecords int
+	FailedRecords    int
+	Errors           []FeedError
+
+	polls   int
+	process func(feed *Feed)
+}
+
+// FeedError is a record of a feed that could not be processed.
+type FeedError struct {
+	Code      int
+	Message   string
+	SellerSku string
+}
+
+func (f *Feed) fail(err FeedError) {
+	f.FailedRecords++
+	f.Errors = append(f.Errors, err)
+}
+
+// Feed returns a copy of the feed with the given id.
+func (s *Server) Feed(id string) (Feed, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.findFeed(id)
+	if feed == nil {
+		return Feed{}, false
+	}
+
+	return *feed, true
+}
+
+// ProcessFeeds processes all queued feeds without waiting for them to be polled.
+func (s *Server) ProcessFeeds() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, feed := range s.feeds {
+		s.finishFeed(feed)
+	}
+}
+
+// queueFeed creates a Queued feed, process applies its changes once it is finished.
+func (s *Server) queueFeed(action string, totalRecords int, process func(feed *Feed)) (success, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := &Feed{
+		Id:           newUuid(),
+		Action:       action,
+		Status:       FeedStatusQueued,
+		CreationDate: s.now(),
+		UpdatedDate:  s.now(),
+		TotalRecords: totalRecords,
+		process:      process,
+	}
+
+	s.feeds = append(s.feeds, feed)
+
+	return success{requestId: feed.Id}, nil
+}
+
+func (s *Server) findFeed(id string) *Feed {
+	for _, feed := range s.feeds {
+		if feed.Id == id {
+			return feed
+		}
+	}
+
+	return nil
+}
+
+// pollFeed advances a queued feed, it is finished once it was polled feedPolls times.
+func (s *Server) pollFeed(feed *Feed) {
+	if feed.Status != FeedStatusQueued {
+		return
+	}
+
+	if feed.polls < s.feedPolls {
+		feed.polls++
+		return
+	}
+
+	s.finishFeed(feed)
+}
+
+func (s *Server) finishFeed(feed *Feed) {
+	if feed.Status != FeedStatusQueued {
+		return
+	}
+
+	feed.process(feed)
+	feed.Status = FeedStatusFinished
+	feed.UpdatedDate = s.now()
+}
+
+func (s *Server) feedList(_ url.Values, _ []byte) (success, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []interface{}
+	for _, feed := range s.feeds {
+		s.pollFeed(feed)
+		items = append(items, s.feedJson(feed))
+	}
+
+	return success{
+		responseType: "Feed",
+		body:         map[string]interface{}{"Feed": list(items)},
+	}, nil
+}
+
+func (s *Server) feedStatus(params url.Values, _ []byte) (success, *apiError) {
+	id := params.Get("FeedID")
+	if id == "" {
+		return success{}, newApiError(ErrorCodeMissingParameter, "Parameter FeedID is mandatory")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed := s.findFeed(id)
+	if feed == nil {
+		return success{}, newApiError(ErrorCodeInvalidRequest, "Invalid Feed ID")
+	}
+
+	s.pollFeed(feed)
+
+	detail := s.feedJson(feed)
+
+	var errors []interface{}
+	for _, err := range feed.Errors {
+		errors = append(errors, map[string]interface{}{
+			"Code":      strconv.Itoa(err.Code),
+			"Message":   err.Message,
+			"SellerSku": err.SellerSku,
+		})
+	}
+	detail["FeedErrors"] = map[string]interface{}{"Error": list(errors)}
+	detail["FeedWarnings"] = ""
+
+	return success{
+		responseType: "FeedDetail",
+		body:         map[string]interface{}{"FeedDetail": detail},
+	}, nil
+}
+
+func (s *Server) feedJson(feed *Feed) map[string]interface{} {
+	return map[string]interface{}{
+		"Feed":             feed.Id,
+		"Status":           feed.Status,
+		"Action":           feed.Action,
+		"CreationDate":     s.formatTime(feed.CreationDate),
+		"UpdatedDate":      s.formatTime(feed.UpdatedDate),
+		"Source":           "api",
+		"TotalRecords":     strconv.Itoa(feed.TotalRecords),
+		"ProcessedRecords": strconv.Itoa(feed.ProcessedRecords),
+		"FailedRecords":    strconv.Itoa(feed.FailedRecords),
+		"FailureReports":   "",
+	}
+}
+
+func newUuid() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
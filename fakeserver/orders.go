@@ -0,0 +1,428 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"github.com/GFG/seller-center-sdk-go/model"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const apiParamTimeFormat = "2006-01-02T15:04:05"
+
+// orderItemTransitions lists the statuses an order item may be in to move to a status.
+var orderItemTransitions = map[string][]string{
+	model.OrderItemStatusPacked:      {model.OrderItemStatusPending},
+	model.OrderItemStatusReadyToShip: {model.OrderItemStatusPending, model.OrderItemStatusPacked},
+	model.OrderItemStatusShipped:     {model.OrderItemStatusReadyToShip},
+	model.OrderItemStatusCanceled:    {model.OrderItemStatusPending, model.OrderItemStatusPacked, model.OrderItemStatusReadyToShip},
+}
+
+// Order is the state the server keeps for an order.
+type Order struct {
+	OrderId           int
+	OrderNumber       string
+	CustomerFirstName string
+	CustomerLastName  string
+	PaymentMethod     string
+	Price             float64
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Items             []OrderItem
+}
+
+type OrderItem struct {
+	OrderItemId      int
+	Sku              string
+	ShopSku          string
+	Name             string
+	ItemPrice        float64
+	PaidPrice        float64
+	Currency         string
+	Status           string
+	ShipmentProvider string
+	DeliveryType     string
+	TrackingCode     string
+	Reason           string
+	ReasonDetail     string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// AddOrder adds an order and returns it with the ids, timestamps and statuses filled in that were not set.
+// Order items default to pending.
+func (s *Server) AddOrder(order Order) Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if order.OrderId == 0 {
+		order.OrderId = s.newId()
+	}
+	if order.OrderNumber == "" {
+		order.OrderNumber = strconv.Itoa(order.OrderId)
+	}
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = s.now()
+	}
+	if order.UpdatedAt.IsZero() {
+		order.UpdatedAt = order.CreatedAt
+	}
+
+	order.Items = append([]OrderItem(nil), order.Items...)
+	for i := range order.Items {
+		item := &order.Items[i]
+		if item.OrderItemId == 0 {
+			item.OrderItemId = s.newId()
+		}
+		if item.Status == "" {
+			item.Status = model.OrderItemStatusPending
+		}
+		if item.CreatedAt.IsZero() {
+			item.CreatedAt = order.CreatedAt
+		}
+		if item.UpdatedAt.IsZero() {
+			item.UpdatedAt = order.UpdatedAt
+		}
+	}
+
+	stored := order
+	s.orders = append(s.orders, &stored)
+
+	return copyOrder(&stored)
+}
+
+// Order returns a copy of the order with the given id.
+func (s *Server) Order(orderId int) (Order, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := s.findOrder(orderId)
+	if order == nil {
+		return Order{}, false
+	}
+
+	return copyOrder(order), true
+}
+
+func copyOrder(order *Order) Order {
+	c := *order
+	c.Items = append([]OrderItem(nil), order.Items...)
+
+	return c
+}
+
+func (s *Server) findOrder(orderId int) *Order {
+	for _, order := range s.orders {
+		if order.OrderId == orderId {
+			return order
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) findOrderItem(orderItemId int) (*Order, *OrderItem) {
+	for _, order := range s.orders {
+		for i := range order.Items {
+			if order.Items[i].OrderItemId == orderItemId {
+				return order, &order.Items[i]
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Server) getOrders(params url.Values, _ []byte) (success, *apiError) {
+	filters := map[string]func(order *Order, t time.Time) bool{
+		"CreatedAfter":  func(order *Order, t time.Time) bool { return order.CreatedAt.After(t) },
+		"CreatedBefore": func(order *Order, t time.Time) bool { return order.CreatedAt.Before(t) },
+		"UpdatedAfter":  func(order *Order, t time.Time) bool { return order.UpdatedAt.After(t) },
+		"UpdatedBefore": func(order *Order, t time.Time) bool { return order.UpdatedAt.Before(t) },
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	times := map[string]time.Time{}
+	for param := range filters {
+		if raw := params.Get(param); raw != "" {
+			t, err := time.ParseInLocation(apiParamTimeFormat, raw, s.location)
+			if err != nil {
+				return success{}, newApiError(ErrorCodeInvalidRequest, "Invalid %s", param)
+			}
+
+			times[param] = t
+		}
+	}
+
+	status := params.Get("Status")
+
+	var items []interface{}
+	for _, order := range s.orders {
+		matches := status == "" || containsString(orderStatuses(order), status)
+		for param, t := range times {
+			matches = matches && filters[param](order, t)
+		}
+
+		if matches {
+			items = append(items, s.orderJson(order))
+		}
+	}
+
+	items, apiErr := paginate(items, params)
+	if apiErr != nil {
+		return success{}, apiErr
+	}
+
+	return success{
+		responseType: "Orders",
+		body:         map[string]interface{}{"Orders": map[string]interface{}{"Order": list(items)}},
+	}, nil
+}
+
+func (s *Server) getOrder(params url.Values, _ []byte) (success, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, apiErr := s.orderFromParam(params, "OrderId")
+	if apiErr != nil {
+		return success{}, apiErr
+	}
+
+	return success{
+		responseType: "Orders",
+		body:         map[string]interface{}{"Orders": map[string]interface{}{"Order": s.orderJson(order)}},
+	}, nil
+}
+
+func (s *Server) getOrderItems(params url.Values, _ []byte) (success, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, apiErr := s.orderFromParam(params, "OrderId")
+	if apiErr != nil {
+		return success{}, apiErr
+	}
+
+	return success{
+		responseType: "OrderItems",
+		body:         map[string]interface{}{"OrderItems": map[string]interface{}{"OrderItem": s.orderItemsJson(order)}},
+	}, nil
+}
+
+func (s *Server) getMultipleOrderItems(params url.Values, _ []byte) (success, *apiError) {
+	orderIds, apiErr := intListParam(params, "OrderIdList")
+	if apiErr != nil {
+		return success{}, apiErr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []interface{}
+	for _, orderId := range orderIds {
+		order := s.findOrder(orderId)
+		if order == nil {
+			return success{}, newApiError(ErrorCodeInvalidOrderId, "Invalid Order ID %d", orderId)
+		}
+
+		items = append(items, map[string]interface{}{
+			"OrderId":     strconv.Itoa(order.OrderId),
+			"OrderNumber": order.OrderNumber,
+			"OrderItems":  map[string]interface{}{"OrderItem": s.orderItemsJson(order)},
+		})
+	}
+
+	return success{
+		responseType: "Orders",
+		body:         map[string]interface{}{"Orders": map[string]interface{}{"Order": list(items)}},
+	}, nil
+}
+
+func (s *Server) setStatusToPackedByMarketplace(params url.Values, _ []byte) (success, *apiError) {
+	orderItemIds, apiErr := intListParam(params, "OrderItemIds")
+	if apiErr != nil {
+		return success{}, apiErr
+	}
+
+	return s.setStatus(orderItemIds, model.OrderItemStatusPacked, func(item *OrderItem) {
+		item.DeliveryType = params.Get("DeliveryType")
+		item.ShipmentProvider = params.Get("ShippingProvider")
+	})
+}
+
+func (s *Server) setStatusToReadyToShip(params url.Values, _ []byte) (success, *apiError) {
+	orderItemIds, apiErr := intListParam(params, "OrderItemIds")
+	if apiErr != nil {
+		return success{}, apiErr
+	}
+
+	return s.setStatus(orderItemIds, model.OrderItemStatusReadyToShip, func(item *OrderItem) {
+		item.DeliveryType = params.Get("DeliveryType")
+		item.ShipmentProvider = params.Get("ShippingProvider")
+		item.TrackingCode = params.Get("TrackingNumber")
+	})
+}
+
+func (s *Server) setStatusToShipped(params url.Values, _ []byte) (success, *apiError) {
+	orderItemId, apiErr := intParam(params, "OrderItemId")
+	if apiErr != nil {
+		return success{}, apiErr
+	}
+
+	return s.setStatus([]int{orderItemId}, model.OrderItemStatusShipped, func(item *OrderItem) {})
+}
+
+func (s *Server) setStatusToCanceled(params url.Values, _ []byte) (success, *apiError) {
+	orderItemId, apiErr := intParam(params, "OrderItemId")
+	if apiErr != nil {
+		return success{}, apiErr
+	}
+
+	return s.setStatus([]int{orderItemId}, model.OrderItemStatusCanceled, func(item *OrderItem) {
+		item.Reason = params.Get("Reason")
+		item.ReasonDetail = params.Get("ReasonDetail")
+	})
+}
+
+// setStatus moves all order items to the status or, if one of them can not make the transition, none.
+func (s *Server) setStatus(orderItemIds []int, status string, update func(item *OrderItem)) (success, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := make([]*OrderItem, len(orderItemIds))
+	orders := make([]*Order, len(orderItemIds))
+	for i, orderItemId := range orderItemIds {
+		order, item := s.findOrderItem(orderItemId)
+		if item == nil {
+			return success{}, newApiError(ErrorCodeInvalidOrderItem, "Invalid Order Item ID %d", orderItemId)
+		}
+
+		if !containsString(orderItemTransitions[status], item.Status) {
+			return success{}, newApiError(ErrorCodeInvalidOrderStatus, "Order item %d can not change from %s to %s", orderItemId, item.Status, status)
+		}
+
+		items[i], orders[i] = item, order
+	}
+
+	for i, item := range items {
+		item.Status = status
+		item.UpdatedAt = s.now()
+		update(item)
+		orders[i].UpdatedAt = item.UpdatedAt
+	}
+
+	return success{}, nil
+}
+
+func (s *Server) orderFromParam(params url.Values, param string) (*Order, *apiError) {
+	orderId, apiErr := intParam(params, param)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	order := s.findOrder(orderId)
+	if order == nil {
+		return nil, newApiError(ErrorCodeInvalidOrderId, "Invalid Order ID %d", orderId)
+	}
+
+	return order, nil
+}
+
+func orderStatuses(order *Order) []string {
+	var statuses []string
+	for _, item := range order.Items {
+		if !containsString(statuses, item.Status) {
+			statuses = append(statuses, item.Status)
+		}
+	}
+	sort.Strings(statuses)
+
+	return statuses
+}
+
+func (s *Server) orderJson(order *Order) map[string]interface{} {
+	var statuses []interface{}
+	for _, status := range orderStatuses(order) {
+		statuses = append(statuses, status)
+	}
+
+	return map[string]interface{}{
+		"OrderId":           strconv.Itoa(order.OrderId),
+		"OrderNumber":       order.OrderNumber,
+		"CustomerFirstName": order.CustomerFirstName,
+		"CustomerLastName":  order.CustomerLastName,
+		"PaymentMethod":     order.PaymentMethod,
+		"Price":             formatPrice(order.Price),
+		"CreatedAt":         s.formatTime(order.CreatedAt),
+		"UpdatedAt":         s.formatTime(order.UpdatedAt),
+		"ItemsCount":        strconv.Itoa(len(order.Items)),
+		"Statuses":          map[string]interface{}{"Status": list(statuses)},
+	}
+}
+
+func (s *Server) orderItemsJson(order *Order) interface{} {
+	var items []interface{}
+	for _, item := range order.Items {
+		items = append(items, map[string]interface{}{
+			"OrderItemId":      strconv.Itoa(item.OrderItemId),
+			"OrderId":          strconv.Itoa(order.OrderId),
+			"Name":             item.Name,
+			"Sku":              item.Sku,
+			"ShopSku":          item.ShopSku,
+			"ItemPrice":        formatPrice(item.ItemPrice),
+			"PaidPrice":        formatPrice(item.PaidPrice),
+			"Currency":         item.Currency,
+			"Status":           item.Status,
+			"ShipmentProvider": item.ShipmentProvider,
+			"TrackingCode":     item.TrackingCode,
+			"Reason":           item.Reason,
+			"ReasonDetail":     item.ReasonDetail,
+			"CreatedAt":        s.formatTime(item.CreatedAt),
+			"UpdatedAt":        s.formatTime(item.UpdatedAt),
+		})
+	}
+
+	return list(items)
+}
+
+func intParam(params url.Values, param string) (int, *apiError) {
+	raw := params.Get(param)
+	if raw == "" {
+		return 0, newApiError(ErrorCodeMissingParameter, "Parameter %s is mandatory", param)
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, newApiError(ErrorCodeInvalidRequest, "Invalid %s", param)
+	}
+
+	return value, nil
+}
+
+// intListParam reads a list param in the format [1,2,3].
+func intListParam(params url.Values, param string) ([]int, *apiError) {
+	raw := params.Get(param)
+	if raw == "" {
+		return nil, newApiError(ErrorCodeMissingParameter, "Parameter %s is mandatory", param)
+	}
+
+	var values []int
+	if err := json.Unmarshal([]byte(raw), &values); err != nil || len(values) == 0 {
+		return nil, newApiError(ErrorCodeInvalidRequest, "Invalid %s", param)
+	}
+
+	return values, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,320 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Product is the state the server keeps for a product.
+type Product struct {
+	SellerSku       string
+	ShopSku         string
+	Name            string
+	Description     string
+	Brand           string
+	TaxClass        string
+	Variation       string
+	ParentSku       string
+	Status          string
+	ProductId       string
+	Quantity        int
+	Price           float64
+	SalePrice       float64
+	PrimaryCategory int
+	Images          []string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// productXml is a product of the ProductCreate and ProductUpdate feeds, unset fields are left unchanged.
+type productXml struct {
+	SellerSku       *string  `xml:"SellerSku"`
+	Name            *string  `xml:"Name"`
+	Description     *string  `xml:"Description"`
+	Brand           *string  `xml:"Brand"`
+	TaxClass        *string  `xml:"TaxClass"`
+	Variation       *string  `xml:"Variation"`
+	ParentSku       *string  `xml:"ParentSku"`
+	Status          *string  `xml:"Status"`
+	ProductId       *string  `xml:"ProductId"`
+	Quantity        *int     `xml:"Quantity"`
+	Price           *float64 `xml:"Price"`
+	SalePrice       *float64 `xml:"SalePrice"`
+	PrimaryCategory *int     `xml:"PrimaryCategory"`
+}
+
+type productsXml struct {
+	Products []productXml `xml:"Product"`
+}
+
+type imageXml struct {
+	SellerSku string   `xml:"ProductImage>SellerSku"`
+	Images    []string `xml:"ProductImage>Images>Image"`
+}
+
+func (px productXml) apply(p *Product) {
+	setString(&p.Name, px.Name)
+	setString(&p.Description, px.Description)
+	setString(&p.Brand, px.Brand)
+	setString(&p.TaxClass, px.TaxClass)
+	setString(&p.Variation, px.Variation)
+	setString(&p.ParentSku, px.ParentSku)
+	setString(&p.Status, px.Status)
+	setString(&p.ProductId, px.ProductId)
+
+	if px.Quantity != nil {
+		p.Quantity = *px.Quantity
+	}
+	if px.Price != nil {
+		p.Price = *px.Price
+	}
+	if px.SalePrice != nil {
+		p.SalePrice = *px.SalePrice
+	}
+	if px.PrimaryCategory != nil {
+		p.PrimaryCategory = *px.PrimaryCategory
+	}
+}
+
+func setString(dst *string, value *string) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
+// AddProduct adds or replaces a product.
+func (s *Server) AddProduct(product Product) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.putProduct(product)
+}
+
+// Product returns a copy of the product with the given SellerSku.
+func (s *Server) Product(sellerSku string) (Product, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product := s.findProduct(sellerSku)
+	if product == nil {
+		return Product{}, false
+	}
+
+	return *product, true
+}
+
+func (s *Server) putProduct(product Product) {
+	if product.ShopSku == "" {
+		product.ShopSku = fmt.Sprintf("SC%d", s.newId())
+	}
+
+	if existing := s.findProduct(product.SellerSku); existing != nil {
+		*existing = product
+		return
+	}
+
+	s.products = append(s.products, &product)
+}
+
+func (s *Server) findProduct(sellerSku string) *Product {
+	for _, product := range s.products {
+		if product.SellerSku == sellerSku {
+			return product
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) getProducts(params url.Values, _ []byte) (success, *apiError) {
+	var skus map[string]bool
+	if raw := params.Get("SkuSellerList"); raw != "" {
+		var list []string
+		if err := json.Unmarshal([]byte(raw), &list); err != nil {
+			return success{}, newApiError(ErrorCodeInvalidRequest, "Invalid SkuSellerList")
+		}
+
+		skus = map[string]bool{}
+		for _, sku := range list {
+			skus[sku] = true
+		}
+	}
+
+	search := params.Get("Search")
+	status := params.Get("Status")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []interface{}
+	for _, product := range s.products {
+		if skus != nil && !skus[product.SellerSku] {
+			continue
+		}
+		if search != "" && !strings.Contains(product.Name, search) && !strings.Contains(product.SellerSku, search) {
+			continue
+		}
+		if status != "" && status != "all" && product.Status != status {
+			continue
+		}
+
+		items = append(items, productJson(product))
+	}
+
+	items, apiErr := paginate(items, params)
+	if apiErr != nil {
+		return success{}, apiErr
+	}
+
+	return success{
+		responseType: "Products",
+		body:         map[string]interface{}{"Products": map[string]interface{}{"Product": list(items)}},
+	}, nil
+}
+
+func productJson(product *Product) map[string]interface{} {
+	images := make([]interface{}, len(product.Images))
+	for i, image := range product.Images {
+		images[i] = image
+	}
+
+	mainImage := ""
+	if len(product.Images) > 0 {
+		mainImage = product.Images[0]
+	}
+
+	return map[string]interface{}{
+		"SellerSku":         product.SellerSku,
+		"ShopSku":           product.ShopSku,
+		"Name":              product.Name,
+		"Description":       product.Description,
+		"Brand":             product.Brand,
+		"TaxClass":          product.TaxClass,
+		"Variation":         product.Variation,
+		"ParentSku":         product.ParentSku,
+		"Quantity":          strconv.Itoa(product.Quantity),
+		"Price":             formatPrice(product.Price),
+		"SalePrice":         formatPrice(product.SalePrice),
+		"Status":            product.Status,
+		"ProductId":         product.ProductId,
+		"MainImage":         mainImage,
+		"Images":            map[string]interface{}{"Image": list(images)},
+		"PrimaryCategoryId": strconv.Itoa(product.PrimaryCategory),
+	}
+}
+
+func formatPrice(price float64) string {
+	if price == 0 {
+		return ""
+	}
+
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
+
+func (s *Server) productCreate(_ url.Values, body []byte) (success, *apiError) {
+	var request productsXml
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return success{}, newApiError(ErrorCodeInvalidRequest, "Invalid Request Format")
+	}
+
+	return s.queueFeed("ProductCreate", len(request.Products), func(feed *Feed) {
+		for _, px := range request.Products {
+			if px.SellerSku == nil || *px.SellerSku == "" {
+				feed.fail(FeedError{Message: "Field SellerSku is mandatory"})
+				continue
+			}
+
+			if s.findProduct(*px.SellerSku) != nil {
+				feed.fail(FeedError{Message: "Seller SKU already exists", SellerSku: *px.SellerSku})
+				continue
+			}
+
+			product := Product{SellerSku: *px.SellerSku, Status: "active", CreatedAt: s.now(), UpdatedAt: s.now()}
+			px.apply(&product)
+			s.putProduct(product)
+			feed.ProcessedRecords++
+		}
+	})
+}
+
+func (s *Server) productUpdate(_ url.Values, body []byte) (success, *apiError) {
+	var request productsXml
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return success{}, newApiError(ErrorCodeInvalidRequest, "Invalid Request Format")
+	}
+
+	return s.queueFeed("ProductUpdate", len(request.Products), func(feed *Feed) {
+		for _, px := range request.Products {
+			var product *Product
+			if px.SellerSku != nil {
+				product = s.findProduct(*px.SellerSku)
+			}
+
+			if product == nil {
+				sellerSku := ""
+				if px.SellerSku != nil {
+					sellerSku = *px.SellerSku
+				}
+
+				feed.fail(FeedError{Message: "Seller SKU does not exist", SellerSku: sellerSku})
+				continue
+			}
+
+			px.apply(product)
+			product.UpdatedAt = s.now()
+			feed.ProcessedRecords++
+		}
+	})
+}
+
+func (s *Server) image(_ url.Values, body []byte) (success, *apiError) {
+	var request imageXml
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return success{}, newApiError(ErrorCodeInvalidRequest, "Invalid Request Format")
+	}
+
+	return s.queueFeed("Image", 1, func(feed *Feed) {
+		product := s.findProduct(request.SellerSku)
+		if product == nil {
+			feed.fail(FeedError{Message: "Seller SKU does not exist", SellerSku: request.SellerSku})
+			return
+		}
+
+		product.Images = append([]string(nil), request.Images...)
+		product.UpdatedAt = s.now()
+		feed.ProcessedRecords++
+	})
+}
+
+// paginate applies the Offset and Limit params.
+func paginate(items []interface{}, params url.Values) ([]interface{}, *apiError) {
+	if raw := params.Get("Offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return nil, newApiError(ErrorCodeInvalidRequest, "Invalid Offset")
+		}
+
+		if offset > len(items) {
+			offset = len(items)
+		}
+		items = items[offset:]
+	}
+
+	if raw := params.Get("Limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			return nil, newApiError(ErrorCodeInvalidRequest, "Invalid Limit")
+		}
+
+		if limit > 0 && limit < len(items) {
+			items = items[:limit]
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,311 @@
+// Package fakeserver provides an in-memory Seller Center API for integration tests. It verifies request
+// signatures, reads gzipped XML POST bodies and answers with SuccessResponse and ErrorResponse envelopes.
+package fakeserver
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/GFG/seller-center-sdk-go/client"
+	"github.com/clbanning/mxj"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const scTimeFormat = "2006-01-02 15:04:05"
+
+// Error codes of the ErrorResponse envelopes the server answers with.
+const (
+	ErrorCodeMissingParameter   = "1"
+	ErrorCodeTimestampExpired   = "3"
+	ErrorCodeInvalidTimestamp   = "4"
+	ErrorCodeInvalidRequest     = "5"
+	ErrorCodeInvalidSignature   = "7"
+	ErrorCodeInvalidAction      = "8"
+	ErrorCodeAccessDenied       = "9"
+	ErrorCodeInvalidOrderId     = "16"
+	ErrorCodeInvalidOrderItem   = "20"
+	ErrorCodeInvalidOrderStatus = "73"
+	ErrorCodeInvalidCallbackUrl = "98"
+)
+
+// apiError is answered as ErrorResponse envelope.
+type apiError struct {
+	code    string
+	message string
+}
+
+func newApiError(code string, format string, args ...interface{}) *apiError {
+	number, _ := strconv.Atoi(code)
+
+	return &apiError{
+		code:    code,
+		message: fmt.Sprintf("E%03d: %s", number, fmt.Sprintf(format, args...)),
+	}
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// success is the result of an action, answered as SuccessResponse envelope.
+type success struct {
+	requestId    string
+	responseType string
+	body         interface{}
+}
+
+type actionHandler func(params url.Values, body []byte) (success, *apiError)
+
+// Server is a fake Seller Center API keeping products, orders, feeds and webhooks in memory.
+// It implements http.Handler and is safe for concurrent use.
+type Server struct {
+	user string
+	key  string
+
+	mu              sync.Mutex
+	now             func() time.Time
+	location        *time.Location
+	maxTimestampAge time.Duration
+	feedPolls       int
+	nextId          int
+	products        []*Product
+	orders          []*Order
+	feeds           []*Feed
+	webhooks        []*Webhook
+	actions         map[string]actionHandler
+}
+
+// New returns a server accepting requests signed for the given user and API key.
+func New(user string, key string) *Server {
+	s := &Server{
+		user:      user,
+		key:       key,
+		now:       time.Now,
+		location:  time.UTC,
+		feedPolls: 1,
+	}
+
+	s.actions = map[string]actionHandler{
+		"GetProducts":                    s.getProducts,
+		"ProductCreate":                  s.productCreate,
+		"ProductUpdate":                  s.productUpdate,
+		"Image":                          s.image,
+		"GetOrders":                      s.getOrders,
+		"GetOrder":                       s.getOrder,
+		"GetOrderItems":                  s.getOrderItems,
+		"GetMultipleOrderItems":          s.getMultipleOrderItems,
+		"SetStatusToPackedByMarketplace": s.setStatusToPackedByMarketplace,
+		"SetStatusToReadyToShip":         s.setStatusToReadyToShip,
+		"SetStatusToShipped":             s.setStatusToShipped,
+		"SetStatusToCanceled":            s.setStatusToCanceled,
+		"FeedList":                       s.feedList,
+		"FeedStatus":                     s.feedStatus,
+		"CreateWebhook":                  s.createWebhook,
+		"GetWebhooks":                    s.getWebhooks,
+		"GetWebhookEntities":             s.getWebhookEntities,
+	}
+
+	return s
+}
+
+// Start serves the server on a local httptest.Server, which the caller has to close.
+func (s *Server) Start() *httptest.Server {
+	return httptest.NewServer(s)
+}
+
+// SetClock replaces the clock used for timestamps and the age check of signed requests.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
+
+// SetLocation sets the zone of the venue. Seller Center sends dates like CreationDate without zone in the time of
+// the venue, and its timestamps with the offset of that zone. The default is UTC, independent of the local zone.
+func (s *Server) SetLocation(location *time.Location) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.location = location
+}
+
+// formatTime formats t as date without zone in the time of the venue.
+func (s *Server) formatTime(t time.Time) string {
+	return t.In(s.location).Format(scTimeFormat)
+}
+
+// SetMaxTimestampAge rejects requests whose Timestamp differs more than d from the server clock.
+// Zero, the default, accepts any timestamp.
+func (s *Server) SetMaxTimestampAge(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxTimestampAge = d
+}
+
+// SetFeedPolls sets how many times a feed is reported as Queued by FeedStatus and FeedList before it is
+// processed and reported as Finished. The default is 1, zero processes feeds on the first poll.
+func (s *Server) SetFeedPolls(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.feedPolls = n
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	action := params.Get("Action")
+
+	body, err := readBody(r)
+	if err != nil {
+		s.write(w, params, action, success{}, newApiError(ErrorCodeInvalidRequest, "Invalid Request Format: %s", err))
+		return
+	}
+
+	if apiErr := s.verify(params); apiErr != nil {
+		s.write(w, params, action, success{}, apiErr)
+		return
+	}
+
+	handler, ok := s.actions[action]
+	if !ok {
+		s.write(w, params, action, success{}, newApiError(ErrorCodeInvalidAction, "Invalid Action"))
+		return
+	}
+
+	result, apiErr := handler(params, body)
+	s.write(w, params, action, result, apiErr)
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+
+		reader = gzipReader
+	}
+
+	return ioutil.ReadAll(reader)
+}
+
+// verify checks the mandatory params and the signature the way the client builds them.
+func (s *Server) verify(params url.Values) *apiError {
+	for _, param := range []string{"Action", "UserID", "Version", "Timestamp", "Signature"} {
+		if params.Get(param) == "" {
+			return newApiError(ErrorCodeMissingParameter, "Parameter %s is mandatory", param)
+		}
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, params.Get("Timestamp"))
+	if err != nil {
+		return newApiError(ErrorCodeInvalidTimestamp, "Invalid Timestamp format")
+	}
+
+	s.mu.Lock()
+	now, maxTimestampAge := s.now(), s.maxTimestampAge
+	s.mu.Unlock()
+
+	if age := now.Sub(timestamp); maxTimestampAge > 0 && (age > maxTimestampAge || age < -maxTimestampAge) {
+		return newApiError(ErrorCodeTimestampExpired, "Timestamp has expired")
+	}
+
+	if params.Get("UserID") != s.user {
+		return newApiError(ErrorCodeAccessDenied, "Access Denied")
+	}
+
+	if client.NewSigner(s.user, s.key).Verify(params) != nil {
+		return newApiError(ErrorCodeInvalidSignature, "Login failed. Signature mismatching")
+	}
+
+	return nil
+}
+
+func (s *Server) write(w http.ResponseWriter, params url.Values, action string, result success, apiErr *apiError) {
+	s.mu.Lock()
+	timestamp := s.now().In(s.location).Format(client.ResponseTimestampFormat)
+	s.mu.Unlock()
+
+	var envelope map[string]interface{}
+	if apiErr != nil {
+		envelope = map[string]interface{}{
+			"ErrorResponse": map[string]interface{}{
+				"Head": map[string]interface{}{
+					"RequestAction": action,
+					"ErrorType":     "Sender",
+					"ErrorCode":     apiErr.code,
+					"ErrorMessage":  apiErr.message,
+					"Timestamp":     timestamp,
+				},
+				"Body": "",
+			},
+		}
+	} else {
+		body := result.body
+		if body == nil {
+			body = ""
+		}
+
+		envelope = map[string]interface{}{
+			"SuccessResponse": map[string]interface{}{
+				"Head": map[string]interface{}{
+					"RequestId":     result.requestId,
+					"RequestAction": action,
+					"ResponseType":  result.responseType,
+					"Timestamp":     timestamp,
+				},
+				"Body": body,
+			},
+		}
+	}
+
+	if params.Get("Format") == "XML" {
+		data, err := mxj.Map(envelope).Xml()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.Write(append([]byte(xml.Header), data...))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(envelope)
+}
+
+// newId returns the next id of the server wide sequence.
+func (s *Server) newId() int {
+	s.nextId++
+
+	return s.nextId
+}
+
+// list wraps items the way Seller Center does: a single item is an object, several are an array.
+func list(items []interface{}) interface{} {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+
+	return items
+}
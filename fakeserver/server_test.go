@@ -0,0 +1,312 @@
+package fakeserver_test
+
+import (
+	"context"
+	"errors"
+	"github.com/GFG/seller-center-sdk-go/client"
+	"github.com/GFG/seller-center-sdk-go/fakeserver"
+	"github.com/GFG/seller-center-sdk-go/model"
+	"github.com/GFG/seller-center-sdk-go/resource"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+const (
+	testUser = "user@sellercenter.net"
+	testKey  = "b1bdb357ced10fe4e9a69840cdd4f0e9c03d77fe"
+)
+
+func newTestClient(t *testing.T, apiUrl string, key string, opts ...client.Option) client.Client {
+	logger := log.New(ioutil.Discard, "", 0)
+
+	config, err := client.NewClientConfig(apiUrl, testUser, key, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client.NewClient(*config, logger, opts...)
+}
+
+func Test_Can_Create_Products_And_Poll_The_Feed(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	products := resource.NewProduct(newTestClient(t, httpServer.URL, testKey))
+	feeds := resource.NewFeed(newTestClient(t, httpServer.URL, testKey))
+
+	feedId, err := products.ProductCreate([]resource.ProductBuilder{
+		*products.InitProduct().WithSellerSku("sku-1").WithName("Shirt").WithBrand("Brand").WithPrice(19.99).WithQuantity(3),
+		*products.InitProduct().WithSellerSku("sku-2").WithName("Shoe").WithBrand("Brand").WithPrice(49.5).WithQuantity(1),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	feedStatus, err := feeds.FeedStatus(feedId)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if feedStatus.Status != fakeserver.FeedStatusQueued || feedStatus.Action != "ProductCreate" || feedStatus.TotalRecords != 2 {
+		t.Fatalf("expected queued ProductCreate feed with 2 records, actual `%+v`.", feedStatus)
+	}
+
+	feedStatus, err = feeds.FeedStatus(feedId)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if feedStatus.Status != fakeserver.FeedStatusFinished || feedStatus.ProcessedRecords != 2 || feedStatus.FailedRecords != 0 {
+		t.Fatalf("expected finished feed with 2 processed records, actual `%+v`.", feedStatus)
+	}
+
+	feedId, err = products.ProductUpdate([]resource.ProductBuilder{
+		*products.InitProduct().WithSellerSku("sku-1").WithPrice(14.99),
+		*products.InitProduct().WithSellerSku("sku-3").WithPrice(1),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if _, err = products.ProductImage("sku-2", model.Images{"https://example.com/shoe.jpg"}); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	server.ProcessFeeds()
+
+	feedStatus, err = feeds.FeedStatus(feedId)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if feedStatus.ProcessedRecords != 1 || len(feedStatus.FeedErrors.Errors) != 1 || feedStatus.FeedErrors.Errors[0].SellerSku != "sku-3" {
+		t.Fatalf("expected feed error for sku-3, actual `%+v`.", feedStatus)
+	}
+
+	feedList, err := feeds.FeedList()
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if len(feedList.Feeds) != 3 {
+		t.Fatalf("expected 3 feeds, actual %d.", len(feedList.Feeds))
+	}
+
+	sellerSkus := []string{"sku-2", "sku-1"}
+	result, err := products.GetProducts(resource.GetProductsParams{SkuSellerList: &sellerSkus})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if len(result.Products) != 2 {
+		t.Fatalf("expected 2 products, actual %d.", len(result.Products))
+	}
+
+	shirt, shoe := result.Products[0], result.Products[1]
+	if shirt.SellerSku != "sku-1" || shirt.Name != "Shirt" || shirt.Price != 14.99 || shirt.Quantity != 3 {
+		t.Fatalf("unexpected product `%+v`.", shirt)
+	}
+
+	if !reflect.DeepEqual(shoe.Images, model.Images{"https://example.com/shoe.jpg"}) || shoe.MainImage != "https://example.com/shoe.jpg" {
+		t.Fatalf("unexpected product images `%+v`.", shoe)
+	}
+}
+
+func Test_Can_Move_Order_Items_Through_Statuses(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	order := server.AddOrder(fakeserver.Order{
+		CustomerFirstName: "Jane",
+		Price:             30,
+		Items: []fakeserver.OrderItem{
+			{Sku: "sku-1", Name: "Shirt", ItemPrice: 10},
+			{Sku: "sku-2", Name: "Shoe", ItemPrice: 20},
+		},
+	})
+	first, second := order.Items[0].OrderItemId, order.Items[1].OrderItemId
+
+	orders := resource.NewOrder(newTestClient(t, httpServer.URL, testKey))
+
+	pending := "pending"
+	result, err := orders.GetOrders(resource.GetOrdersParams{Status: &pending})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if len(result.Orders) != 1 || int(result.Orders[0].OrderId) != order.OrderId || result.Orders[0].CustomerFirstName != "Jane" {
+		t.Fatalf("unexpected orders `%+v`.", result)
+	}
+
+	if _, err = orders.SetStatusToReadyToShip([]int{first, second}, model.DeliveryTypeDropshipping, "DHL", "TRACK-1"); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if _, err = orders.SetStatusToShipped(first); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if _, err = orders.SetStatusToShipped(first); !errors.Is(err, resource.ErrInvalidOrderItemStatus) {
+		t.Fatalf("expected ErrInvalidOrderItemStatus, actual `%v`.", err)
+	}
+
+	if _, err = orders.SetStatusToCanceled(second, "out of stock", ""); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	items, err := orders.GetOrderItems(order.OrderId)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if len(items.Items) != 2 ||
+		items.Items[0].Status != model.OrderItemStatusShipped || items.Items[0].TrackingCode != "TRACK-1" ||
+		items.Items[1].Status != model.OrderItemStatusCanceled || items.Items[1].Reason != "out of stock" {
+		t.Fatalf("unexpected order items `%+v`.", items)
+	}
+
+	fetched, err := orders.GetOrder(order.OrderId)
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	expectedStatuses := model.Status{model.OrderItemStatusCanceled, model.OrderItemStatusShipped}
+	if !reflect.DeepEqual(fetched.Statuses, expectedStatuses) {
+		t.Fatalf("expected statuses `%v`, actual `%v`.", expectedStatuses, fetched.Statuses)
+	}
+}
+
+func Test_Can_Create_Webhooks(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	webhooks := resource.NewWebhook(newTestClient(t, httpServer.URL, testKey))
+
+	if _, err := webhooks.CreateWebhook("ftp://example.com", []string{"onOrderCreated"}); !errors.Is(err, resource.ErrInvalidWebhookCallback) {
+		t.Fatalf("expected ErrInvalidWebhookCallback, actual `%v`.", err)
+	}
+
+	if _, err := webhooks.CreateWebhook("https://example.com/hook", []string{"onOrderCreated", "onFeedCompleted"}); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	result, err := webhooks.GetWebhooks()
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	expected := model.Webhooks{Webhooks: []model.Webhook{{
+		WebhookId:     server.Webhooks()[0].WebhookId,
+		CallbackUrl:   "https://example.com/hook",
+		WebhookSource: "api",
+		Events:        model.WebhookEvents{Events: []model.WebhookEvent{"onOrderCreated", "onFeedCompleted"}},
+	}}}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected `%+v`, actual `%+v`.", expected, result)
+	}
+
+	entities, err := webhooks.GetWebhookEntities()
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if len(entities.WebhookEntities) != 3 {
+		t.Fatalf("expected 3 webhook entities, actual `%+v`.", entities)
+	}
+}
+
+func Test_Rejects_Requests_With_Invalid_Signature(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	_, err := resource.NewFeed(newTestClient(t, httpServer.URL, "wrong-key")).FeedList()
+
+	if !errors.Is(err, resource.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, actual `%v`.", err)
+	}
+}
+
+func Test_Can_Answer_In_Xml_Format(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	server.AddProduct(fakeserver.Product{SellerSku: "sku-1", Name: "Shirt", Quantity: 2})
+
+	products := resource.NewProduct(newTestClient(t, httpServer.URL, testKey, client.WithResponseFormat(client.FormatXML)))
+
+	result, err := products.GetProducts(resource.GetProductsParams{})
+	if err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if len(result.Products) != 1 || result.Products[0].SellerSku != "sku-1" || result.Products[0].Quantity != 2 {
+		t.Fatalf("unexpected products `%+v`.", result)
+	}
+}
+
+func Test_Can_Stream_Products_In_Json_And_Xml_Format(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	server.AddProduct(fakeserver.Product{SellerSku: "sku-1", Name: "Shirt", Quantity: 2})
+	server.AddProduct(fakeserver.Product{SellerSku: "sku-2", Name: "Shoe", Quantity: 1})
+
+	for _, format := range []string{client.FormatJSON, client.FormatXML} {
+		products := resource.NewProduct(newTestClient(t, httpServer.URL, testKey, client.WithResponseFormat(format)))
+
+		var skus []string
+		err := products.StreamProducts(resource.GetProductsParams{}, func(product model.Product) error {
+			skus = append(skus, product.SellerSku)
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error `%v` in format %s.", err, format)
+		}
+
+		if expected := []string{"sku-1", "sku-2"}; !reflect.DeepEqual(skus, expected) {
+			t.Fatalf("expected: %v - actual: %v", expected, skus)
+		}
+	}
+
+	orders := resource.NewOrder(newTestClient(t, httpServer.URL, testKey, client.WithMaxResponseSize(16)))
+	if err := orders.StreamOrders(resource.GetOrdersParams{}, func(model.Order) error { return nil }); !errors.Is(err, client.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, actual `%v`.", err)
+	}
+}
+
+func Test_Can_Get_Order_Items_In_Batch(t *testing.T) {
+	server := fakeserver.New(testUser, testKey)
+	httpServer := server.Start()
+	defer httpServer.Close()
+
+	var orderIds []int
+	for i := 0; i < 10; i++ {
+		order := server.AddOrder(fakeserver.Order{Items: []fakeserver.OrderItem{{Sku: "sku-1"}, {Sku: "sku-2"}}})
+		orderIds = append(orderIds, order.OrderId)
+	}
+	orderIds = append(orderIds, 999999)
+
+	orders := resource.NewOrder(newTestClient(t, httpServer.URL, testKey))
+
+	results := orders.GetOrderItemsBatch(context.Background(), client.NewBatch(4), orderIds)
+
+	result, ok := results.Get(strconv.Itoa(orderIds[3]))
+	if !ok || result.Err != nil || len(result.Value.(model.OrderItems).Items) != 2 {
+		t.Fatalf("unexpected result `%+v`.", result)
+	}
+
+	if errs := results.Errors(); len(errs) != 1 || errs["999999"] == nil {
+		t.Fatalf("expected error for unknown order only, actual `%v`.", errs)
+	}
+}
@@ -0,0 +1,131 @@
+package fakeserver
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+)
+
+// webhookEntities are the entities and events webhooks can be created for, by entity name.
+var webhookEntities = []struct {
+	name   string
+	events []string
+}{
+	{"Feed", []string{"onFeedCreated", "onFeedCompleted"}},
+	{"Product", []string{"onProductCreated", "onProductUpdated", "onProductQcStatusChanged"}},
+	{"Order", []string{"onOrderCreated", "onOrderItemsStatusChanged"}},
+}
+
+// Webhook is the state the server keeps for a webhook.
+type Webhook struct {
+	WebhookId   string
+	CallbackUrl string
+	Events      []string
+}
+
+type webhookXml struct {
+	CallbackUrl string   `xml:"Webhook>CallbackUrl"`
+	Events      []string `xml:"Webhook>Events>Event"`
+}
+
+// Webhooks returns copies of the created webhooks.
+func (s *Server) Webhooks() []Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhooks := make([]Webhook, len(s.webhooks))
+	for i, webhook := range s.webhooks {
+		webhooks[i] = *webhook
+		webhooks[i].Events = append([]string(nil), webhook.Events...)
+	}
+
+	return webhooks
+}
+
+func (s *Server) createWebhook(_ url.Values, body []byte) (success, *apiError) {
+	var request webhookXml
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return success{}, newApiError(ErrorCodeInvalidRequest, "Invalid Request Format")
+	}
+
+	callbackUrl, err := url.Parse(request.CallbackUrl)
+	if err != nil || (callbackUrl.Scheme != "http" && callbackUrl.Scheme != "https") || callbackUrl.Host == "" {
+		return success{}, newApiError(ErrorCodeInvalidCallbackUrl, "Invalid Webhook Callback Url")
+	}
+
+	if len(request.Events) == 0 {
+		return success{}, newApiError(ErrorCodeMissingParameter, "Parameter Events is mandatory")
+	}
+
+	for _, event := range request.Events {
+		if !isWebhookEvent(event) {
+			return success{}, newApiError(ErrorCodeInvalidRequest, "Invalid Webhook Event %s", event)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.webhooks = append(s.webhooks, &Webhook{
+		WebhookId:   strconv.Itoa(s.newId()),
+		CallbackUrl: request.CallbackUrl,
+		Events:      request.Events,
+	})
+
+	return success{}, nil
+}
+
+func isWebhookEvent(event string) bool {
+	for _, entity := range webhookEntities {
+		if containsString(entity.events, event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) getWebhooks(_ url.Values, _ []byte) (success, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []interface{}
+	for _, webhook := range s.webhooks {
+		var events []interface{}
+		for _, event := range webhook.Events {
+			events = append(events, event)
+		}
+
+		items = append(items, map[string]interface{}{
+			"WebhookId":     webhook.WebhookId,
+			"CallbackUrl":   webhook.CallbackUrl,
+			"WebhookSource": "api",
+			"Events":        map[string]interface{}{"Event": list(events)},
+		})
+	}
+
+	return success{
+		responseType: "Webhooks",
+		body:         map[string]interface{}{"Webhooks": map[string]interface{}{"Webhook": list(items)}},
+	}, nil
+}
+
+func (s *Server) getWebhookEntities(_ url.Values, _ []byte) (success, *apiError) {
+	var items []interface{}
+	for _, entity := range webhookEntities {
+		var events []interface{}
+		for _, event := range entity.events {
+			events = append(events, map[string]interface{}{"EventName": event, "EventAlias": event})
+		}
+
+		items = append(items, map[string]interface{}{
+			"Name":   entity.name,
+			"Events": map[string]interface{}{"Event": list(events)},
+		})
+	}
+
+	return success{
+		responseType: "Entities",
+		body:         map[string]interface{}{"Entities": map[string]interface{}{"Entity": list(items)}},
+	}, nil
+}
@@ -26,6 +26,15 @@ const (
 	FailureReasonFailed         = FailureReasonType("failed")
 	FailureReasonReturnRejected = FailureReasonType("return_rejected")
 
+	OrderItemStatusPending     = "pending"
+	OrderItemStatusPacked      = "packed"
+	OrderItemStatusReadyToShip = "ready_to_ship"
+	OrderItemStatusShipped     = "shipped"
+	OrderItemStatusDelivered   = "delivered"
+	OrderItemStatusCanceled    = "canceled"
+	OrderItemStatusReturned    = "returned"
+	OrderItemStatusFailed      = "failed"
+
 	scTimeFormat = "2006-01-02 15:04:05"
 )
 
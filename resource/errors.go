@@ -0,0 +1,149 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/GFG/seller-center-sdk-go/client"
+	"github.com/buger/jsonparser"
+	"net/http"
+	"strings"
+)
+
+// ErrorCode is a known Seller Center error code. APIErrors match it with errors.Is, e.g.
+// errors.Is(err, ErrInvalidSignature). Codes are constants, so they cannot be changed by callers.
+type ErrorCode string
+
+// Known Seller Center error codes.
+const (
+	ErrMissingParameter       ErrorCode = "E001"
+	ErrInvalidVersion         ErrorCode = "E002"
+	ErrTimestampExpired       ErrorCode = "E003"
+	ErrInvalidTimestamp       ErrorCode = "E004"
+	ErrInvalidRequestFormat   ErrorCode = "E005"
+	ErrInternal               ErrorCode = "E006"
+	ErrInvalidSignature       ErrorCode = "E007"
+	ErrInvalidAction          ErrorCode = "E008"
+	ErrAccessDenied           ErrorCode = "E009"
+	ErrInsecureChannel        ErrorCode = "E010"
+	ErrRequestTooBig          ErrorCode = "E011"
+	ErrInvalidOrderItemStatus ErrorCode = "E073"
+	ErrInvalidWebhookCallback ErrorCode = "E098"
+)
+
+// ErrTooManyRequests matches the client.HTTPError of a 429 response. Seller Center has no error code for it.
+const ErrTooManyRequests = client.HTTPStatus(http.StatusTooManyRequests)
+
+var errorCodeMessages = map[ErrorCode]string{
+	ErrMissingParameter:       "Parameter is mandatory",
+	ErrInvalidVersion:         "Invalid Version",
+	ErrTimestampExpired:       "Timestamp has expired",
+	ErrInvalidTimestamp:       "Invalid Timestamp format",
+	ErrInvalidRequestFormat:   "Invalid Request Format",
+	ErrInternal:               "Unexpected internal error",
+	ErrInvalidSignature:       "Login failed. Signature mismatching",
+	ErrInvalidAction:          "Invalid Action",
+	ErrAccessDenied:           "Access Denied",
+	ErrInsecureChannel:        "Insecure Channel",
+	ErrRequestTooBig:          "Request too Big",
+	ErrInvalidOrderItemStatus: "Invalid order item status",
+	ErrInvalidWebhookCallback: "Invalid Webhook Callback Url",
+}
+
+func (c ErrorCode) Error() string {
+	if message, ok := errorCodeMessages[c]; ok {
+		return fmt.Sprintf("%s: %s", string(c), message)
+	}
+
+	return string(c)
+}
+
+// ApiResponseError is the former name of APIError.
+type ApiResponseError = APIError
+
+// APIError is an ErrorResponse returned by Seller Center. Type is either `Sender` or `Server`.
+type APIError struct {
+	Code          string
+	Message       string
+	Type          string
+	RequestAction string
+	Details       []ErrorDetail
+}
+
+// ErrorDetail is an entry of the ErrorDetail list in the body of an ErrorResponse, e.g. per SellerSku.
+type ErrorDetail struct {
+	Field       string `json:"Field"`
+	Message     string `json:"Message"`
+	Value       string `json:"Value"`
+	SellerSku   string `json:"SellerSku"`
+	OrderId     string `json:"OrderId"`
+	OrderItemId string `json:"OrderItemId"`
+}
+
+func newApiResponseError(errorResponse client.ErrorResponse) error {
+	errorHead := errorResponse.HeadObject
+
+	return &APIError{
+		Code:          errorHead.ErrorCode,
+		Message:       errorHead.ErrorMessage,
+		Type:          errorHead.ErrorType,
+		RequestAction: errorHead.RequestAction,
+		Details:       parseErrorDetails(errorResponse.GetBody()),
+	}
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Is reports whether the target is an ErrorCode or an APIError with the same code. Codes are compared by
+// their number, so `E007`, `E7` and `7` are the same code.
+func (e *APIError) Is(target error) bool {
+	var code string
+	switch t := target.(type) {
+	case ErrorCode:
+		code = string(t)
+	case *APIError:
+		code = t.Code
+	}
+
+	if code == "" {
+		return false
+	}
+
+	return normalizeErrorCode(e.Code) == normalizeErrorCode(code)
+}
+
+func normalizeErrorCode(code string) string {
+	code = strings.TrimLeft(strings.ToUpper(strings.TrimSpace(code)), "E")
+	code = strings.TrimLeft(code, "0")
+
+	return code
+}
+
+func parseErrorDetails(rawBody []byte) []ErrorDetail {
+	if len(rawBody) == 0 {
+		return nil
+	}
+
+	rawDetails, dataType, _, err := jsonparser.Get(rawBody, "ErrorDetail")
+	if err != nil || len(rawDetails) == 0 {
+		return nil
+	}
+
+	var details []ErrorDetail
+	switch dataType {
+	case jsonparser.Array:
+		if err := json.Unmarshal(rawDetails, &details); nil != err {
+			return nil
+		}
+	case jsonparser.Object:
+		var detail ErrorDetail
+		if err := json.Unmarshal(rawDetails, &detail); nil != err {
+			return nil
+		}
+
+		details = []ErrorDetail{detail}
+	}
+
+	return details
+}
@@ -0,0 +1,105 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"github.com/GFG/seller-center-sdk-go/client"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_Api_Error_Can_Be_Matched_With_Errors_Is(t *testing.T) {
+	clientErrorResponse := client.ErrorResponse{
+		HeadObject: client.HeadErrorResponse{
+			RequestAction: "GetOrders",
+			ErrorType:     "Sender",
+			ErrorCode:     "7",
+			ErrorMessage:  "E7: Login failed. Signature mismatch",
+		},
+	}
+
+	fakeClient := client.FakeClient{
+		FakeResponse: clientErrorResponse,
+		FakeError:    nil,
+	}
+
+	_, err := NewOrder(fakeClient).GetOrders(GetOrdersParams{})
+
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected error to match ErrInvalidSignature, actual `%v`.", err)
+	}
+
+	if errors.Is(err, ErrTimestampExpired) {
+		t.Fatal("expected error not to match ErrTimestampExpired.")
+	}
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.Type != "Sender" || apiError.RequestAction != "GetOrders" {
+		t.Fatalf("expected APIError with all head fields, actual `%#v`.", err)
+	}
+}
+
+func Test_Api_Error_Carries_Error_Details(t *testing.T) {
+	clientErrorResponse := client.ErrorResponse{
+		HeadObject: client.HeadErrorResponse{
+			RequestAction: "ProductCreate",
+			ErrorType:     "Sender",
+			ErrorCode:     "201",
+			ErrorMessage:  "E201: %s Invalid Feed",
+		},
+		Body: []byte(`{"ErrorDetail": [
+			{"Field": "Price", "Message": "Field must contain a number", "Value": "abc", "SellerSku": "sku-1"},
+			{"Field": "Brand", "Message": "Brand does not exist", "Value": "Foo", "SellerSku": "sku-2"}
+		]}`),
+	}
+
+	fakeClient := client.FakeClient{
+		FakeResponse: clientErrorResponse,
+		FakeError:    nil,
+	}
+
+	_, err := NewProduct(fakeClient).ProductCreate([]ProductBuilder{})
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("expected APIError, actual `%v`.", err)
+	}
+
+	expected := []ErrorDetail{
+		{Field: "Price", Message: "Field must contain a number", Value: "abc", SellerSku: "sku-1"},
+		{Field: "Brand", Message: "Brand does not exist", Value: "Foo", SellerSku: "sku-2"},
+	}
+
+	if !reflect.DeepEqual(expected, apiError.Details) {
+		t.Fatalf("error details were not parsed. expected: `%v` - received: `%v`.", expected, apiError.Details)
+	}
+}
+
+func Test_Api_Error_Codes_Are_Normalized(t *testing.T) {
+	for _, code := range []string{"E003", "E3", "3", "003", "e003"} {
+		err := &APIError{Code: code}
+
+		if !errors.Is(err, ErrTimestampExpired) {
+			t.Fatalf("expected code `%s` to match ErrTimestampExpired.", code)
+		}
+	}
+}
+
+func Test_Too_Many_Requests_Is_Matched_By_Http_Status(t *testing.T) {
+	err := fmt.Errorf("sync failed: %w", &client.HTTPError{StatusCode: http.StatusTooManyRequests})
+
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("expected error to match ErrTooManyRequests, actual `%v`.", err)
+	}
+
+	if errors.Is(&client.HTTPError{StatusCode: http.StatusServiceUnavailable}, ErrTooManyRequests) {
+		t.Fatal("expected a 503 not to match ErrTooManyRequests.")
+	}
+}
+
+func Test_Error_Codes_Describe_Themselves(t *testing.T) {
+	if ErrTimestampExpired.Error() != "E003: Timestamp has expired" {
+		t.Fatalf("unexpected error message `%s`.", ErrTimestampExpired)
+	}
+}
@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/GFG/seller-center-sdk-go/client"
 	"github.com/GFG/seller-center-sdk-go/model"
@@ -16,18 +17,29 @@ func NewFeed(client client.Client) FeedResource {
 }
 
 func (fr FeedResource) FeedList() (model.FeedList, error) {
+	return fr.FeedListContext(context.Background())
+}
+
+func (fr FeedResource) FeedListContext(ctx context.Context) (model.FeedList, error) {
+	feedList, _, err := fr.feedList(ctx)
+
+	return feedList, err
+}
+
+// feedList also returns the response, whose Head tells the time of Seller Center.
+func (fr FeedResource) feedList(ctx context.Context) (model.FeedList, client.Response, error) {
 	request := client.NewGenericRequest("FeedList", client.MethodGET)
 	request.SetVersion(client.V1)
 
-	response, err := fr.client.Call(request)
+	response, err := client.CallContext(ctx, fr.client, request)
 
 	if err != nil {
-		return model.FeedList{}, err
+		return model.FeedList{}, nil, err
 	}
 
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
-		return model.FeedList{}, newApiResponseError(errorResponse.HeadObject)
+		return model.FeedList{}, nil, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -37,30 +49,34 @@ func (fr FeedResource) FeedList() (model.FeedList, error) {
 
 	if err != nil {
 		if err == jsonparser.KeyPathNotFoundError {
-			return feedList, nil
+			return feedList, response, nil
 		}
 
-		return feedList, err
+		return feedList, nil, err
 	}
 
 	if len(rawFeeds) == 0 {
-		return feedList, nil
+		return feedList, response, nil
 	}
 
 	err = json.Unmarshal(rawBody, &feedList)
 	if err != nil {
-		return model.FeedList{}, err
+		return model.FeedList{}, nil, err
 	}
 
-	return feedList, nil
+	return feedList, response, nil
 }
 
 func (fr FeedResource) FeedStatus(feedIdentifier string) (model.FeedStatus, error) {
+	return fr.FeedStatusContext(context.Background(), feedIdentifier)
+}
+
+func (fr FeedResource) FeedStatusContext(ctx context.Context, feedIdentifier string) (model.FeedStatus, error) {
 	request := client.NewGenericRequest("FeedStatus", client.MethodGET)
 	request.SetVersion(client.V1)
 	request.SetRequestParam("FeedID", feedIdentifier)
 
-	response, err := fr.client.Call(request)
+	response, err := client.CallContext(ctx, fr.client, request)
 
 	feedStatus := model.FeedStatus{}
 
@@ -70,7 +86,7 @@ func (fr FeedResource) FeedStatus(feedIdentifier string) (model.FeedStatus, erro
 
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
-		return feedStatus, newApiResponseError(errorResponse.HeadObject)
+		return feedStatus, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -95,3 +111,21 @@ func (fr FeedResource) FeedStatus(feedIdentifier string) (model.FeedStatus, erro
 
 	return feedStatus, nil
 }
+
+// FeedStatusBatch gets the status of every feed with the batch. Results are keyed by feed id and carry
+// model.FeedStatus values.
+func (fr FeedResource) FeedStatusBatch(ctx context.Context, batch *client.Batch, feedIdentifiers []string) client.BatchResults {
+	operations := make([]client.Operation, len(feedIdentifiers))
+	for i, feedIdentifier := range feedIdentifiers {
+		feedIdentifier := feedIdentifier
+
+		operations[i] = client.Operation{
+			Key: feedIdentifier,
+			Run: func(ctx context.Context) (interface{}, error) {
+				return fr.FeedStatusContext(ctx, feedIdentifier)
+			},
+		}
+	}
+
+	return batch.Run(ctx, operations)
+}
@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"context"
 	"errors"
 	"github.com/GFG/seller-center-sdk-go/client"
 	"github.com/GFG/seller-center-sdk-go/model"
@@ -49,8 +50,8 @@ func Test_Get_FeedList_Can_Handle_ErrorResponse(t *testing.T) {
 	feedList, err := resource.FeedList()
 
 	expectedError := &ApiResponseError{
-		"E27",
-		"Wrong signature",
+		Code:    "E27",
+		Message: "Wrong signature",
 	}
 
 	if !reflect.DeepEqual(expectedError, err) {
@@ -155,8 +156,8 @@ func Test_Get_FeedStatus_Can_Handle_ErrorResponse(t *testing.T) {
 	feedList, err := resource.FeedStatus("1234")
 
 	expectedError := &ApiResponseError{
-		"E27",
-		"Wrong signature",
+		Code:    "E27",
+		Message: "Wrong signature",
 	}
 
 	if !reflect.DeepEqual(expectedError, err) {
@@ -220,3 +221,49 @@ func Test_Get_FeedStatus_Can_Handle_Response(t *testing.T) {
 		t.Fatalf("did not receive proper feedStatus. received: `%v`.", feedStatus)
 	}
 }
+
+func Test_Get_FeedList_Context_Returns_Context_Error(t *testing.T) {
+	fakeClient := client.FakeClient{
+		FakeResponse: client.SuccessResponse{},
+		FakeError:    nil,
+	}
+
+	resource := NewFeed(fakeClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	feedList, err := resource.FeedListContext(ctx)
+
+	if err != context.Canceled {
+		t.Fatalf("context error was not returned. expected: `%v` - received: `%v`.", context.Canceled, err)
+	}
+
+	empty := model.FeedList{}
+
+	if !reflect.DeepEqual(empty, feedList) {
+		t.Fatalf("did not return empty feedList. received: `%v`.", feedList)
+	}
+}
+
+// callOnlyClient implements client.Client without client.ContextClient, like clients written before contexts.
+type callOnlyClient struct {
+	client.FakeClient
+}
+
+func (c callOnlyClient) CallContext() {}
+
+func Test_Get_FeedList_Calls_Clients_Without_Context_Support(t *testing.T) {
+	resource := NewFeed(callOnlyClient{client.FakeClient{FakeResponse: client.SuccessResponse{Body: []byte(`{"Feed": ""}`)}}})
+
+	if _, err := resource.FeedList(); err != nil {
+		t.Fatalf("expected error to be nil, actual `%v`.", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := resource.FeedListContext(ctx); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, actual `%v`.", err)
+	}
+}
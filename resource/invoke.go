@@ -0,0 +1,278 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/GFG/seller-center-sdk-go/client"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Invoke calls an action the SDK does not wrap yet, see InvokeContext.
+func Invoke(c client.Client, action string, method string, version string, params map[string]string, postData interface{}, out interface{}) error {
+	return InvokeContext(context.Background(), c, action, method, version, params, postData, out)
+}
+
+// InvokeContext calls the action with the params and, for POST requests, postData marshalled to XML. An
+// ErrorResponse is returned as *APIError. Otherwise the Body of the SuccessResponse is decoded into out, a
+// pointer, unless out is nil. An empty version means client.V1.
+//
+// The body is normalized to the type of out the way the models do it by hand: a single object is decoded into
+// a slice as its only element, empty strings into empty slices, structs and maps, and numbers and booleans sent
+// as strings into numeric and boolean fields. Types implementing json.Unmarshaler decode their values themselves.
+func InvokeContext(ctx context.Context, c client.Client, action string, method string, version string, params map[string]string, postData interface{}, out interface{}) error {
+	r := client.NewGenericRequest(action, method)
+	if version != "" {
+		r.SetVersion(version)
+	}
+
+	for key, value := range params {
+		r.SetRequestParam(key, value)
+	}
+
+	if postData != nil {
+		r.SetPostData(postData)
+	}
+
+	response, err := client.CallContext(ctx, c, r)
+
+	if err != nil {
+		return err
+	}
+
+	if response.IsError() {
+		errorResponse, _ := response.(client.ErrorResponse)
+
+		return newApiResponseError(errorResponse)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	rawBody := response.GetBody()
+	if len(rawBody) == 0 {
+		return nil
+	}
+
+	normalized, err := normalizeJson(rawBody, reflect.TypeOf(out))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(normalized, out)
+}
+
+// normalizeJson rewrites raw so that it decodes into a value of type t.
+func normalizeJson(raw json.RawMessage, t reflect.Type) (json.RawMessage, error) {
+	if t == nil || reflect.PtrTo(t).Implements(jsonUnmarshalerType) || t.Implements(jsonUnmarshalerType) {
+		return raw, nil
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+			return raw, nil
+		}
+	}
+
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return raw, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return raw, nil
+		}
+
+		switch trimmed[0] {
+		case '[':
+			return normalizeArray(trimmed, t.Elem())
+		case '{':
+			element, err := normalizeJson(trimmed, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+
+			return append(append(json.RawMessage{'['}, element...), ']'), nil
+		}
+
+		return emptyStringToNull(trimmed), nil
+	case reflect.Struct:
+		if trimmed[0] == '{' {
+			return normalizeObject(trimmed, func(key string) (reflect.Type, bool) {
+				return structFieldType(t, key)
+			})
+		}
+
+		return emptyStringToNull(trimmed), nil
+	case reflect.Map:
+		if trimmed[0] == '{' {
+			return normalizeObject(trimmed, func(string) (reflect.Type, bool) {
+				return t.Elem(), true
+			})
+		}
+
+		return emptyStringToNull(trimmed), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return unquoteNumber(trimmed), nil
+	case reflect.Bool:
+		return unquoteBool(trimmed), nil
+	}
+
+	return raw, nil
+}
+
+func normalizeArray(raw json.RawMessage, elem reflect.Type) (json.RawMessage, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(raw, &elements); err != nil {
+		return nil, err
+	}
+
+	for i, element := range elements {
+		normalized, err := normalizeJson(element, elem)
+		if err != nil {
+			return nil, err
+		}
+
+		elements[i] = normalized
+	}
+
+	return json.Marshal(elements)
+}
+
+func normalizeObject(raw json.RawMessage, fieldType func(key string) (reflect.Type, bool)) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	for key, value := range fields {
+		t, ok := fieldType(key)
+		if !ok {
+			continue
+		}
+
+		normalized, err := normalizeJson(value, t)
+		if err != nil {
+			return nil, err
+		}
+
+		fields[key] = normalized
+	}
+
+	return json.Marshal(fields)
+}
+
+// structFieldType returns the type of the field encoding/json decodes the key into, preferring an exact match
+// of the name over a case-insensitive one.
+func structFieldType(t reflect.Type, key string) (reflect.Type, bool) {
+	var fold reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+
+			if tagName := strings.Split(tag, ",")[0]; tagName != "" {
+				name = tagName
+			} else if field.Anonymous {
+				name = ""
+			}
+		} else if field.Anonymous {
+			name = ""
+		}
+
+		// ... fields of embedded structs are promoted
+		if name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				if ft, ok := structFieldType(embedded, key); ok {
+					return ft, true
+				}
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == key {
+			return field.Type, true
+		}
+
+		if fold == nil && strings.EqualFold(name, key) {
+			fold = field.Type
+		}
+	}
+
+	return fold, fold != nil
+}
+
+// emptyStringToNull replaces the empty string Seller Center sends for empty lists and objects with null.
+func emptyStringToNull(raw json.RawMessage) json.RawMessage {
+	if string(raw) == `""` {
+		return json.RawMessage("null")
+	}
+
+	return raw
+}
+
+func unquoteNumber(raw json.RawMessage) json.RawMessage {
+	if raw[0] != '"' {
+		return raw
+	}
+
+	value, err := strconv.Unquote(string(raw))
+	if err != nil {
+		return raw
+	}
+
+	if value == "" {
+		return json.RawMessage("null")
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err != nil || !json.Valid([]byte(value)) {
+		return raw
+	}
+
+	return json.RawMessage(value)
+}
+
+func unquoteBool(raw json.RawMessage) json.RawMessage {
+	if raw[0] != '"' {
+		return raw
+	}
+
+	value, err := strconv.Unquote(string(raw))
+	if err != nil {
+		return raw
+	}
+
+	switch strings.ToLower(value) {
+	case "1", "true":
+		return json.RawMessage("true")
+	case "0", "false":
+		return json.RawMessage("false")
+	case "":
+		return json.RawMessage("null")
+	}
+
+	return raw
+}
@@ -0,0 +1,116 @@
+package resource
+
+import (
+	"errors"
+	"github.com/GFG/seller-center-sdk-go/client"
+	"github.com/GFG/seller-center-sdk-go/model"
+	"reflect"
+	"testing"
+)
+
+type invokeShipmentProviders struct {
+	ShipmentProviders struct {
+		ShipmentProvider []invokeShipmentProvider
+	}
+}
+
+type invokeShipmentProvider struct {
+	Name                   string
+	Default                bool
+	ApiIntegration         bool
+	TrackingCodeValidation int `json:"TrackingCodeValidationRegex"`
+	Options                map[string][]string
+	CreatedAt              model.ScTimestamp
+}
+
+func Test_Invoke_Normalizes_Single_Objects_And_Strings(t *testing.T) {
+	fakeClient := client.FakeClient{
+		FakeResponse: client.SuccessResponse{
+			Body: []byte(`{"ShipmentProviders": {"ShipmentProvider": {"Name": "DHL", "Default": "1", "ApiIntegration": "0", "TrackingCodeValidationRegex": "12", "Options": {"Types": "", "Zones": ["A", "B"]}, "CreatedAt": "2018-07-06 15:37:57"}}}`),
+		},
+	}
+
+	var providers invokeShipmentProviders
+	if err := Invoke(fakeClient, "GetShipmentProviders", client.MethodGET, "", nil, nil, &providers); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	actual := providers.ShipmentProviders.ShipmentProvider
+	if len(actual) != 1 {
+		t.Fatalf("expected 1 provider, actual `%+v`.", actual)
+	}
+
+	expected := invokeShipmentProvider{
+		Name:                   "DHL",
+		Default:                true,
+		TrackingCodeValidation: 12,
+		Options:                map[string][]string{"Types": nil, "Zones": {"A", "B"}},
+		CreatedAt:              actual[0].CreatedAt,
+	}
+	if !reflect.DeepEqual(actual[0], expected) {
+		t.Fatalf("expected: %+v - actual: %+v", expected, actual[0])
+	}
+}
+
+func Test_Invoke_Unquotes_All_Numeric_Kinds(t *testing.T) {
+	fakeClient := client.FakeClient{
+		FakeResponse: client.SuccessResponse{
+			Body: []byte(`{"Quantity": "7", "Position": "3", "Id": "42", "Price": "9.5"}`),
+		},
+	}
+
+	var out struct {
+		Quantity uint8
+		Position uintptr
+		Id       uint64
+		Price    float32
+	}
+	if err := Invoke(fakeClient, "GetStock", client.MethodGET, "", nil, nil, &out); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if out.Quantity != 7 || out.Position != 3 || out.Id != 42 || out.Price != 9.5 {
+		t.Fatalf("unexpected numbers `%+v`.", out)
+	}
+}
+
+func Test_Invoke_Decodes_Empty_Lists_And_Models(t *testing.T) {
+	fakeClient := client.FakeClient{
+		FakeResponse: client.SuccessResponse{Body: []byte(`{"ShipmentProviders": ""}`)},
+	}
+
+	var providers invokeShipmentProviders
+	if err := Invoke(fakeClient, "GetShipmentProviders", client.MethodGET, "", nil, nil, &providers); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if len(providers.ShipmentProviders.ShipmentProvider) != 0 {
+		t.Fatalf("expected no providers, actual `%+v`.", providers)
+	}
+
+	fakeClient.FakeResponse = client.SuccessResponse{Body: []byte(`{"Orders": {"Order": {"OrderId": "42"}}}`)}
+
+	var orders model.Orders
+	if err := Invoke(fakeClient, "GetOrders", client.MethodGET, client.V1, map[string]string{"Limit": "1"}, nil, &orders); err != nil {
+		t.Fatalf("unexpected error `%v`.", err)
+	}
+
+	if len(orders.Orders) != 1 || orders.Orders[0].OrderId != 42 {
+		t.Fatalf("unexpected orders `%+v`.", orders)
+	}
+}
+
+func Test_Invoke_Returns_Api_Error(t *testing.T) {
+	fakeClient := client.FakeClient{
+		FakeResponse: client.ErrorResponse{
+			HeadObject: client.HeadErrorResponse{RequestAction: "GetShipmentProviders", ErrorType: "Sender", ErrorCode: "8", ErrorMessage: "E008: Invalid Action"},
+		},
+	}
+
+	var providers invokeShipmentProviders
+	err := Invoke(fakeClient, "GetShipmentProviders", client.MethodGET, "", nil, nil, &providers)
+
+	if !errors.Is(err, ErrInvalidAction) {
+		t.Fatalf("expected ErrInvalidAction, actual `%v`.", err)
+	}
+}
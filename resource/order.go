@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -33,7 +34,64 @@ func NewOrder(client client.Client) OrderResource {
 }
 
 func (or OrderResource) GetOrders(params GetOrdersParams) (model.Orders, error) {
+	return or.GetOrdersContext(context.Background(), params)
+}
+
+func (or OrderResource) GetOrdersContext(ctx context.Context, params GetOrdersParams) (model.Orders, error) {
+	r := newGetOrdersRequest(params)
+
+	response, err := client.CallContext(ctx, or.client, r)
+
+	if err != nil {
+		return model.Orders{}, err
+	}
+
+	if response.IsError() {
+		errorResponse, _ := response.(client.ErrorResponse)
+
+		return model.Orders{}, newApiResponseError(errorResponse)
+	}
+
+	rawBody := response.GetBody()
+
+	var orders model.Orders
+	err = json.Unmarshal(rawBody, &orders)
+
+	return orders, err
+}
+
+func (or OrderResource) StreamOrders(params GetOrdersParams, fn func(model.Order) error) error {
+	return or.StreamOrdersContext(context.Background(), params, fn)
+}
+
+// StreamOrdersContext calls fn for every order of the page described by params. Orders are decoded one at a time
+// while the response is read, so large pages do not have to be held in memory as a whole.
+func (or OrderResource) StreamOrdersContext(ctx context.Context, params GetOrdersParams, fn func(model.Order) error) error {
+	r := newGetOrdersRequest(params)
+
+	response, err := client.Stream(ctx, or.client, r, []string{"Orders", "Order"}, func(element json.RawMessage) error {
+		var order model.Order
+		if err := json.Unmarshal(element, &order); err != nil {
+			return err
+		}
+
+		return fn(order)
+	})
+
+	if err != nil {
+		return err
+	}
 
+	if response.IsError() {
+		errorResponse, _ := response.(client.ErrorResponse)
+
+		return newApiResponseError(errorResponse)
+	}
+
+	return nil
+}
+
+func newGetOrdersRequest(params GetOrdersParams) client.Request {
 	r := client.NewGenericRequest("GetOrders", client.MethodGET)
 	r.SetVersion(client.V1)
 
@@ -65,33 +123,20 @@ func (or OrderResource) GetOrders(params GetOrdersParams) (model.Orders, error)
 		r.SetRequestParam("SortDirection", *params.SortDirection)
 	}
 
-	response, err := or.client.Call(r)
-
-	if err != nil {
-		return model.Orders{}, err
-	}
-
-	if response.IsError() {
-		errorResponse, _ := response.(client.ErrorResponse)
-
-		return model.Orders{}, newApiResponseError(errorResponse.HeadObject)
-	}
-
-	rawBody := response.GetBody()
-
-	var orders model.Orders
-	err = json.Unmarshal(rawBody, &orders)
-
-	return orders, err
+	return r
 }
 
 func (or OrderResource) GetOrder(orderId int) (model.Order, error) {
+	return or.GetOrderContext(context.Background(), orderId)
+}
+
+func (or OrderResource) GetOrderContext(ctx context.Context, orderId int) (model.Order, error) {
 	r := client.NewGenericRequest("GetOrder", client.MethodGET)
 	r.SetVersion(client.V1)
 
 	r.SetRequestParam("OrderId", strconv.Itoa(orderId))
 
-	response, err := or.client.Call(r)
+	response, err := client.CallContext(ctx, or.client, r)
 
 	if err != nil {
 		return model.Order{}, err
@@ -100,7 +145,7 @@ func (or OrderResource) GetOrder(orderId int) (model.Order, error) {
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return model.Order{}, newApiResponseError(errorResponse.HeadObject)
+		return model.Order{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -117,12 +162,16 @@ func (or OrderResource) GetOrder(orderId int) (model.Order, error) {
 }
 
 func (or OrderResource) GetOrderItems(orderId int) (model.OrderItems, error) {
+	return or.GetOrderItemsContext(context.Background(), orderId)
+}
+
+func (or OrderResource) GetOrderItemsContext(ctx context.Context, orderId int) (model.OrderItems, error) {
 	r := client.NewGenericRequest("GetOrderItems", client.MethodGET)
 	r.SetVersion(client.V1)
 
 	r.SetRequestParam("OrderId", strconv.Itoa(orderId))
 
-	response, err := or.client.Call(r)
+	response, err := client.CallContext(ctx, or.client, r)
 
 	if err != nil {
 		return model.OrderItems{}, err
@@ -131,7 +180,7 @@ func (or OrderResource) GetOrderItems(orderId int) (model.OrderItems, error) {
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return model.OrderItems{}, newApiResponseError(errorResponse.HeadObject)
+		return model.OrderItems{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -144,13 +193,35 @@ func (or OrderResource) GetOrderItems(orderId int) (model.OrderItems, error) {
 	return orderItems, nil
 }
 
+// GetOrderItemsBatch gets the items of every order with the batch. Results are keyed by order id and carry
+// model.OrderItems values.
+func (or OrderResource) GetOrderItemsBatch(ctx context.Context, batch *client.Batch, orderIds []int) client.BatchResults {
+	operations := make([]client.Operation, len(orderIds))
+	for i, orderId := range orderIds {
+		orderId := orderId
+
+		operations[i] = client.Operation{
+			Key: strconv.Itoa(orderId),
+			Run: func(ctx context.Context) (interface{}, error) {
+				return or.GetOrderItemsContext(ctx, orderId)
+			},
+		}
+	}
+
+	return batch.Run(ctx, operations)
+}
+
 func (or OrderResource) GetMultipleOrderItems(orderIds []int) (model.OrdersWithItems, error) {
+	return or.GetMultipleOrderItemsContext(context.Background(), orderIds)
+}
+
+func (or OrderResource) GetMultipleOrderItemsContext(ctx context.Context, orderIds []int) (model.OrdersWithItems, error) {
 	r := client.NewGenericRequest("GetMultipleOrderItems", client.MethodGET)
 	r.SetVersion(client.V1)
 
 	r.SetRequestParam("OrderIdList", intSliceToParam(orderIds))
 
-	response, err := or.client.Call(r)
+	response, err := client.CallContext(ctx, or.client, r)
 
 	if err != nil {
 		return model.OrdersWithItems{}, err
@@ -159,7 +230,7 @@ func (or OrderResource) GetMultipleOrderItems(orderIds []int) (model.OrdersWithI
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return model.OrdersWithItems{}, newApiResponseError(errorResponse.HeadObject)
+		return model.OrdersWithItems{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -173,13 +244,17 @@ func (or OrderResource) GetMultipleOrderItems(orderIds []int) (model.OrdersWithI
 }
 
 func (or OrderResource) GetDocument(orderItemIds []int, documentType model.DocumentType) (model.Document, error) {
+	return or.GetDocumentContext(context.Background(), orderItemIds, documentType)
+}
+
+func (or OrderResource) GetDocumentContext(ctx context.Context, orderItemIds []int, documentType model.DocumentType) (model.Document, error) {
 	r := client.NewGenericRequest("GetDocument", client.MethodGET)
 	r.SetVersion(client.V1)
 
 	r.SetRequestParam("OrderItemIds", intSliceToParam(orderItemIds))
 	r.SetRequestParam("DocumentType", string(documentType))
 
-	response, err := or.client.Call(r)
+	response, err := client.CallContext(ctx, or.client, r)
 
 	if err != nil {
 		return model.Document{}, err
@@ -188,7 +263,7 @@ func (or OrderResource) GetDocument(orderItemIds []int, documentType model.Docum
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return model.Document{}, newApiResponseError(errorResponse.HeadObject)
+		return model.Document{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -212,10 +287,14 @@ func (or OrderResource) GetDocument(orderItemIds []int, documentType model.Docum
 }
 
 func (or OrderResource) GetFailureReasons() (map[model.FailureReasonType][]string, error) {
+	return or.GetFailureReasonsContext(context.Background())
+}
+
+func (or OrderResource) GetFailureReasonsContext(ctx context.Context) (map[model.FailureReasonType][]string, error) {
 	r := client.NewGenericRequest("GetFailureReasons", client.MethodGET)
 	r.SetVersion(client.V1)
 
-	response, err := or.client.Call(r)
+	response, err := client.CallContext(ctx, or.client, r)
 
 	if err != nil {
 		return map[model.FailureReasonType][]string{}, err
@@ -224,7 +303,7 @@ func (or OrderResource) GetFailureReasons() (map[model.FailureReasonType][]strin
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return map[model.FailureReasonType][]string{}, newApiResponseError(errorResponse.HeadObject)
+		return map[model.FailureReasonType][]string{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -248,6 +327,10 @@ func (or OrderResource) GetFailureReasons() (map[model.FailureReasonType][]strin
 }
 
 func (or OrderResource) SetStatusToCanceled(orderItemId int, reason string, reasonDetail string) (bool, error) {
+	return or.SetStatusToCanceledContext(context.Background(), orderItemId, reason, reasonDetail)
+}
+
+func (or OrderResource) SetStatusToCanceledContext(ctx context.Context, orderItemId int, reason string, reasonDetail string) (bool, error) {
 	r := client.NewGenericRequest("SetStatusToCanceled", client.MethodPOST)
 	r.SetVersion(client.V1)
 
@@ -255,7 +338,7 @@ func (or OrderResource) SetStatusToCanceled(orderItemId int, reason string, reas
 	r.SetRequestParam("Reason", reason)
 	r.SetRequestParam("ReasonDetail", reasonDetail)
 
-	response, err := or.client.Call(r)
+	response, err := client.CallContext(ctx, or.client, r)
 
 	if err != nil {
 		return false, err
@@ -264,13 +347,17 @@ func (or OrderResource) SetStatusToCanceled(orderItemId int, reason string, reas
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return false, newApiResponseError(errorResponse.HeadObject)
+		return false, newApiResponseError(errorResponse)
 	}
 
 	return true, nil
 }
 
 func (or OrderResource) SetStatusToPackedByMarketplace(orderItemIds []int, deliveryType model.DeliveryType, shippingProvider string) (bool, error) {
+	return or.SetStatusToPackedByMarketplaceContext(context.Background(), orderItemIds, deliveryType, shippingProvider)
+}
+
+func (or OrderResource) SetStatusToPackedByMarketplaceContext(ctx context.Context, orderItemIds []int, deliveryType model.DeliveryType, shippingProvider string) (bool, error) {
 	r := client.NewGenericRequest("SetStatusToPackedByMarketplace", client.MethodPOST)
 	r.SetVersion(client.V1)
 
@@ -278,7 +365,7 @@ func (or OrderResource) SetStatusToPackedByMarketplace(orderItemIds []int, deliv
 	r.SetRequestParam("DeliveryType", string(deliveryType))
 	r.SetRequestParam("ShippingProvider", shippingProvider)
 
-	response, err := or.client.Call(r)
+	response, err := client.CallContext(ctx, or.client, r)
 
 	if err != nil {
 		return false, err
@@ -287,13 +374,17 @@ func (or OrderResource) SetStatusToPackedByMarketplace(orderItemIds []int, deliv
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return false, newApiResponseError(errorResponse.HeadObject)
+		return false, newApiResponseError(errorResponse)
 	}
 
 	return true, nil
 }
 
 func (or OrderResource) SetStatusToReadyToShip(orderItemIds []int, deliveryType model.DeliveryType, shippingProvider string, trackingNumber string) (bool, error) {
+	return or.SetStatusToReadyToShipContext(context.Background(), orderItemIds, deliveryType, shippingProvider, trackingNumber)
+}
+
+func (or OrderResource) SetStatusToReadyToShipContext(ctx context.Context, orderItemIds []int, deliveryType model.DeliveryType, shippingProvider string, trackingNumber string) (bool, error) {
 	r := client.NewGenericRequest("SetStatusToReadyToShip", client.MethodPOST)
 	r.SetVersion(client.V1)
 
@@ -302,7 +393,7 @@ func (or OrderResource) SetStatusToReadyToShip(orderItemIds []int, deliveryType
 	r.SetRequestParam("ShippingProvider", shippingProvider)
 	r.SetRequestParam("TrackingNumber", trackingNumber)
 
-	response, err := or.client.Call(r)
+	response, err := client.CallContext(ctx, or.client, r)
 
 	if err != nil {
 		return false, err
@@ -311,19 +402,23 @@ func (or OrderResource) SetStatusToReadyToShip(orderItemIds []int, deliveryType
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return false, newApiResponseError(errorResponse.HeadObject)
+		return false, newApiResponseError(errorResponse)
 	}
 
 	return true, nil
 }
 
 func (or OrderResource) SetStatusToShipped(orderItemId int) (bool, error) {
+	return or.SetStatusToShippedContext(context.Background(), orderItemId)
+}
+
+func (or OrderResource) SetStatusToShippedContext(ctx context.Context, orderItemId int) (bool, error) {
 	r := client.NewGenericRequest("SetStatusToShipped", client.MethodPOST)
 	r.SetVersion(client.V1)
 
 	r.SetRequestParam("OrderItemId", strconv.Itoa(orderItemId))
 
-	response, err := or.client.Call(r)
+	response, err := client.CallContext(ctx, or.client, r)
 
 	if err != nil {
 		return false, err
@@ -332,7 +427,7 @@ func (or OrderResource) SetStatusToShipped(orderItemId int) (bool, error) {
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return false, newApiResponseError(errorResponse.HeadObject)
+		return false, newApiResponseError(errorResponse)
 	}
 
 	return true, nil
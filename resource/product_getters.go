@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/GFG/seller-center-sdk-go/client"
@@ -28,10 +29,14 @@ type GetProductsParams struct {
 }
 
 func (pr ProductResource) GetBrands() (model.Brands, error) {
+	return pr.GetBrandsContext(context.Background())
+}
+
+func (pr ProductResource) GetBrandsContext(ctx context.Context) (model.Brands, error) {
 	r := client.NewGenericRequest("GetBrands", client.MethodGET)
 	r.SetVersion(client.V1)
 
-	response, err := pr.client.Call(r)
+	response, err := client.CallContext(ctx, pr.client, r)
 
 	if err != nil {
 		return model.Brands{}, err
@@ -40,7 +45,7 @@ func (pr ProductResource) GetBrands() (model.Brands, error) {
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return model.Brands{}, newApiResponseError(errorResponse.HeadObject)
+		return model.Brands{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -64,10 +69,14 @@ func (pr ProductResource) GetBrands() (model.Brands, error) {
 }
 
 func (pr ProductResource) GetCategoryTree() (model.Categories, error) {
+	return pr.GetCategoryTreeContext(context.Background())
+}
+
+func (pr ProductResource) GetCategoryTreeContext(ctx context.Context) (model.Categories, error) {
 	r := client.NewGenericRequest("GetCategoryTree", client.MethodGET)
 	r.SetVersion(client.V1)
 
-	response, err := pr.client.Call(r)
+	response, err := client.CallContext(ctx, pr.client, r)
 
 	if err != nil {
 		return model.Categories{}, err
@@ -76,7 +85,7 @@ func (pr ProductResource) GetCategoryTree() (model.Categories, error) {
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return model.Categories{}, newApiResponseError(errorResponse.HeadObject)
+		return model.Categories{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -100,12 +109,16 @@ func (pr ProductResource) GetCategoryTree() (model.Categories, error) {
 }
 
 func (pr ProductResource) GetCategoryAttributes(categoryId int) (model.Attributes, error) {
+	return pr.GetCategoryAttributesContext(context.Background(), categoryId)
+}
+
+func (pr ProductResource) GetCategoryAttributesContext(ctx context.Context, categoryId int) (model.Attributes, error) {
 	r := client.NewGenericRequest("GetCategoryAttributes", client.MethodGET)
 	r.SetVersion(client.V1)
 
 	r.SetRequestParam("PrimaryCategory", strconv.Itoa(categoryId))
 
-	response, err := pr.client.Call(r)
+	response, err := client.CallContext(ctx, pr.client, r)
 
 	if err != nil {
 		return model.Attributes{}, err
@@ -114,7 +127,7 @@ func (pr ProductResource) GetCategoryAttributes(categoryId int) (model.Attribute
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return model.Attributes{}, newApiResponseError(errorResponse.HeadObject)
+		return model.Attributes{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -133,7 +146,65 @@ func (pr ProductResource) GetCategoryAttributes(categoryId int) (model.Attribute
 }
 
 func (pr ProductResource) GetProducts(params GetProductsParams) (model.Products, error) {
+	return pr.GetProductsContext(context.Background(), params)
+}
+
+func (pr ProductResource) GetProductsContext(ctx context.Context, params GetProductsParams) (model.Products, error) {
+	r := newGetProductsRequest(params)
+
+	response, err := client.CallContext(ctx, pr.client, r)
+
+	if err != nil {
+		return model.Products{}, err
+	}
+
+	if response.IsError() {
+		errorResponse, _ := response.(client.ErrorResponse)
+
+		return model.Products{}, newApiResponseError(errorResponse)
+	}
+
+	rawBody := response.GetBody()
+	var products model.Products
+	if err := json.Unmarshal(rawBody, &products); nil != err {
+		return model.Products{}, err
+	}
+
+	return products, nil
+}
+
+func (pr ProductResource) StreamProducts(params GetProductsParams, fn func(model.Product) error) error {
+	return pr.StreamProductsContext(context.Background(), params, fn)
+}
+
+// StreamProductsContext calls fn for every product of the page described by params. Products are decoded one at a
+// time while the response is read, so large pages do not have to be held in memory as a whole.
+func (pr ProductResource) StreamProductsContext(ctx context.Context, params GetProductsParams, fn func(model.Product) error) error {
+	r := newGetProductsRequest(params)
 
+	response, err := client.Stream(ctx, pr.client, r, []string{"Products", "Product"}, func(element json.RawMessage) error {
+		var product model.Product
+		if err := json.Unmarshal(element, &product); err != nil {
+			return err
+		}
+
+		return fn(product)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if response.IsError() {
+		errorResponse, _ := response.(client.ErrorResponse)
+
+		return newApiResponseError(errorResponse)
+	}
+
+	return nil
+}
+
+func newGetProductsRequest(params GetProductsParams) client.Request {
 	r := client.NewGenericRequest("GetProducts", client.MethodGET)
 	r.SetVersion(client.V1)
 
@@ -195,23 +266,5 @@ func (pr ProductResource) GetProducts(params GetProductsParams) (model.Products,
 		r.SetRequestParam("GlobalIdentifier", param)
 	}
 
-	response, err := pr.client.Call(r)
-
-	if err != nil {
-		return model.Products{}, err
-	}
-
-	if response.IsError() {
-		errorResponse, _ := response.(client.ErrorResponse)
-
-		return model.Products{}, newApiResponseError(errorResponse.HeadObject)
-	}
-
-	rawBody := response.GetBody()
-	var products model.Products
-	if err := json.Unmarshal(rawBody, &products); nil != err {
-		return model.Products{}, err
-	}
-
-	return products, nil
+	return r
 }
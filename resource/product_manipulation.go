@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -35,6 +36,10 @@ func (e *ProductApiWarningError) Error() string {
 }
 
 func (pr ProductResource) ProductImage(sellerSku string, images model.Images) (string, error) {
+	return pr.ProductImageContext(context.Background(), sellerSku, images)
+}
+
+func (pr ProductResource) ProductImageContext(ctx context.Context, sellerSku string, images model.Images) (string, error) {
 	r := client.NewGenericRequest("Image", client.MethodPOST)
 	r.SetVersion(client.V1)
 
@@ -45,7 +50,7 @@ func (pr ProductResource) ProductImage(sellerSku string, images model.Images) (s
 
 	r.SetPostData(postData)
 
-	response, err := pr.client.Call(r)
+	response, err := client.CallContext(ctx, pr.client, r)
 
 	if err != nil {
 		return "", err
@@ -55,6 +60,10 @@ func (pr ProductResource) ProductImage(sellerSku string, images model.Images) (s
 }
 
 func (pr ProductResource) ProductCreate(productBuilders []ProductBuilder) (string, error) {
+	return pr.ProductCreateContext(context.Background(), productBuilders)
+}
+
+func (pr ProductResource) ProductCreateContext(ctx context.Context, productBuilders []ProductBuilder) (string, error) {
 	r := client.NewGenericRequest("ProductCreate", client.MethodPOST)
 	r.SetVersion(client.V1)
 
@@ -68,7 +77,7 @@ func (pr ProductResource) ProductCreate(productBuilders []ProductBuilder) (strin
 
 	r.SetPostData(postData)
 
-	response, err := pr.client.Call(r)
+	response, err := client.CallContext(ctx, pr.client, r)
 
 	if err != nil {
 		return "", err
@@ -78,6 +87,10 @@ func (pr ProductResource) ProductCreate(productBuilders []ProductBuilder) (strin
 }
 
 func (pr ProductResource) ProductUpdate(productBuilders []ProductBuilder) (string, error) {
+	return pr.ProductUpdateContext(context.Background(), productBuilders)
+}
+
+func (pr ProductResource) ProductUpdateContext(ctx context.Context, productBuilders []ProductBuilder) (string, error) {
 	r := client.NewGenericRequest("ProductUpdate", client.MethodPOST)
 	r.SetVersion(client.V1)
 
@@ -91,7 +104,7 @@ func (pr ProductResource) ProductUpdate(productBuilders []ProductBuilder) (strin
 
 	r.SetPostData(postData)
 
-	response, err := pr.client.Call(r)
+	response, err := client.CallContext(ctx, pr.client, r)
 
 	if err != nil {
 		return "", err
@@ -142,7 +155,7 @@ func extractProductPostResponseReturnValues(response client.Response) (string, e
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return "", newApiResponseError(errorResponse.HeadObject)
+		return "", newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -0,0 +1,217 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GFG/seller-center-sdk-go/client"
+	"github.com/GFG/seller-center-sdk-go/model"
+	"time"
+)
+
+// feedLookupTolerance widens the time window of feed lookups for the second precision of Seller Center dates and
+// for errors of the measured clock skew.
+const feedLookupTolerance = time.Minute
+
+// Errors
+var (
+	ErrAmbiguousFeed = errors.New("more than one feed matches")
+)
+
+// AppliedFunc reports whether Seller Center applied a mutation whose call ended with an ambiguous outcome.
+type AppliedFunc func(ctx context.Context, outcome *client.AmbiguousOutcomeError) (bool, error)
+
+// RetryAmbiguous runs call. When it fails with a client.AmbiguousOutcomeError, applied checks whether Seller Center
+// applied the mutation nevertheless, and call is only run once more if it did not. If the check fails, the
+// AmbiguousOutcomeError is returned, as nothing more is known about the outcome.
+func RetryAmbiguous(ctx context.Context, call func(ctx context.Context) error, applied AppliedFunc) error {
+	err := call(ctx)
+
+	var outcome *client.AmbiguousOutcomeError
+	if !errors.As(err, &outcome) {
+		return err
+	}
+
+	ok, checkErr := applied(ctx, outcome)
+	if checkErr != nil {
+		return err
+	}
+
+	if ok {
+		return nil
+	}
+
+	return call(ctx)
+}
+
+func (fr FeedResource) FindFeed(action string, since time.Time, records int, sellerSkus []string) (model.Feed, bool, error) {
+	return fr.FindFeedContext(context.Background(), action, since, records, sellerSkus)
+}
+
+// FindFeedContext returns the feed of the action, e.g. `ProductCreate`, created at or after since, a time of the
+// local clock. Unless records is zero, the feed has to have that number of records. Unless sellerSkus is empty, the
+// SellerSkus in the FeedErrors and FeedWarnings of its FeedStatus have to be among them. If more than one feed
+// matches, an error wrapping ErrAmbiguousFeed is returned instead of guessing.
+//
+// FeedStatus only names the SellerSkus of records with errors or warnings, so a feed without any passes the
+// SellerSku filter. Concurrent feeds of the same action with the same number of records that were processed
+// without errors cannot be told apart; they are reported as ambiguous.
+//
+// Seller Center sends the CreationDate without zone, in the time of the venue. It is read in the zone of the
+// Timestamp of the FeedList response, UTC if there is none, and since is corrected by the clock skew of the client.
+func (fr FeedResource) FindFeedContext(ctx context.Context, action string, since time.Time, records int, sellerSkus []string) (model.Feed, bool, error) {
+	feedList, response, err := fr.feedList(ctx)
+	if err != nil {
+		return model.Feed{}, false, err
+	}
+
+	if skew, ok := client.ClockSkew(fr.client); ok {
+		since = since.Add(skew)
+	}
+
+	location := time.UTC
+	if serverTime, ok := client.ResponseTimestamp(response); ok {
+		location = serverTime.Location()
+	}
+
+	var candidates []model.Feed
+	for _, feed := range feedList.Feeds {
+		if feed.Action != action || (records != 0 && int(feed.TotalRecords) != records) {
+			continue
+		}
+
+		if inLocation(time.Time(feed.CreationDate), location).Before(since) {
+			continue
+		}
+
+		if len(sellerSkus) > 0 {
+			ok, err := fr.feedHasOnlySellerSkus(ctx, feed.Feed, sellerSkus)
+			if err != nil {
+				return model.Feed{}, false, err
+			}
+
+			if !ok {
+				continue
+			}
+		}
+
+		candidates = append(candidates, feed)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return model.Feed{}, false, nil
+	case 1:
+		return candidates[0], true, nil
+	}
+
+	return model.Feed{}, false, fmt.Errorf("%w: %d %s feeds created since %s", ErrAmbiguousFeed, len(candidates), action, since.Format(time.RFC3339))
+}
+
+// feedHasOnlySellerSkus reports whether the records the FeedStatus of the feed reports on are among the sellerSkus.
+// It is true for feeds without errors and warnings.
+func (fr FeedResource) feedHasOnlySellerSkus(ctx context.Context, feedId string, sellerSkus []string) (bool, error) {
+	feedStatus, err := fr.FeedStatusContext(ctx, feedId)
+	if err != nil {
+		return false, err
+	}
+
+	known := make(map[string]bool, len(sellerSkus))
+	for _, sellerSku := range sellerSkus {
+		known[sellerSku] = true
+	}
+
+	for _, feedError := range feedStatus.FeedErrors.Errors {
+		if feedError.SellerSku != "" && !known[feedError.SellerSku] {
+			return false, nil
+		}
+	}
+
+	for _, feedWarning := range feedStatus.FeedWarnings.Warnings {
+		if feedWarning.SellerSku != "" && !known[feedWarning.SellerSku] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// inLocation returns the time with the same wall clock as t in the location.
+func inLocation(t time.Time, location *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+}
+
+func (pr ProductResource) ProductCreateOnce(productBuilders []ProductBuilder) (string, error) {
+	return pr.ProductCreateOnceContext(context.Background(), productBuilders)
+}
+
+// ProductCreateOnceContext is ProductCreateContext, but after an ambiguous outcome it looks for the ProductCreate
+// feed of the products created since the request was sent, see FindFeedContext, before creating them again. If
+// more than one feed matches, the AmbiguousOutcomeError is returned.
+func (pr ProductResource) ProductCreateOnceContext(ctx context.Context, productBuilders []ProductBuilder) (string, error) {
+	return pr.postProductsOnce(ctx, "ProductCreate", productBuilders, pr.ProductCreateContext)
+}
+
+func (pr ProductResource) ProductUpdateOnce(productBuilders []ProductBuilder) (string, error) {
+	return pr.ProductUpdateOnceContext(context.Background(), productBuilders)
+}
+
+// ProductUpdateOnceContext is ProductUpdateContext, but after an ambiguous outcome it looks for the ProductUpdate
+// feed of the products created since the request was sent, see FindFeedContext, before updating them again. If
+// more than one feed matches, the AmbiguousOutcomeError is returned.
+func (pr ProductResource) ProductUpdateOnceContext(ctx context.Context, productBuilders []ProductBuilder) (string, error) {
+	return pr.postProductsOnce(ctx, "ProductUpdate", productBuilders, pr.ProductUpdateContext)
+}
+
+func (pr ProductResource) postProductsOnce(ctx context.Context, action string, productBuilders []ProductBuilder, post func(context.Context, []ProductBuilder) (string, error)) (string, error) {
+	var feedId string
+
+	err := RetryAmbiguous(ctx, func(ctx context.Context) error {
+		var err error
+		feedId, err = post(ctx, productBuilders)
+
+		return err
+	}, func(ctx context.Context, outcome *client.AmbiguousOutcomeError) (bool, error) {
+		since := outcome.SentAt.Add(-feedLookupTolerance)
+
+		sellerSkus := make([]string, 0, len(productBuilders))
+		for _, productBuilder := range productBuilders {
+			if productBuilder.product.SellerSku != nil {
+				sellerSkus = append(sellerSkus, *productBuilder.product.SellerSku)
+			}
+		}
+
+		feed, ok, err := NewFeed(pr.client).FindFeedContext(ctx, action, since, len(productBuilders), sellerSkus)
+		if ok {
+			feedId = feed.Feed
+		}
+
+		return ok, err
+	})
+
+	return feedId, err
+}
+
+// OrderItemsHaveStatus returns an AppliedFunc for RetryAmbiguous checking whether the order items of the order
+// are in the status a SetStatusTo* call moves them to, e.g. model.OrderItemStatusShipped.
+func (or OrderResource) OrderItemsHaveStatus(orderId int, orderItemIds []int, status string) AppliedFunc {
+	return func(ctx context.Context, _ *client.AmbiguousOutcomeError) (bool, error) {
+		orderItems, err := or.GetOrderItemsContext(ctx, orderId)
+		if err != nil {
+			return false, err
+		}
+
+		statuses := make(map[int]string, len(orderItems.Items))
+		for _, item := range orderItems.Items {
+			statuses[int(item.OrderItemId)] = item.Status
+		}
+
+		for _, orderItemId := range orderItemIds {
+			if statuses[orderItemId] != status {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+}
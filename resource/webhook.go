@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"github.com/GFG/seller-center-sdk-go/client"
@@ -16,6 +17,10 @@ func NewWebhook(client client.Client) WebhookResource {
 }
 
 func (wr WebhookResource) CreateWebhook(callbackUrl string, events []string) (bool, error) {
+	return wr.CreateWebhookContext(context.Background(), callbackUrl, events)
+}
+
+func (wr WebhookResource) CreateWebhookContext(ctx context.Context, callbackUrl string, events []string) (bool, error) {
 	r := client.NewGenericRequest("CreateWebhook", client.MethodPOST)
 	r.SetVersion(client.V1)
 
@@ -26,7 +31,7 @@ func (wr WebhookResource) CreateWebhook(callbackUrl string, events []string) (bo
 
 	r.SetPostData(postData)
 
-	response, err := wr.client.Call(r)
+	response, err := client.CallContext(ctx, wr.client, r)
 
 	if err != nil {
 		return false, err
@@ -35,17 +40,21 @@ func (wr WebhookResource) CreateWebhook(callbackUrl string, events []string) (bo
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return false, newApiResponseError(errorResponse.HeadObject)
+		return false, newApiResponseError(errorResponse)
 	}
 
 	return true, nil
 }
 
 func (wr WebhookResource) GetWebhookEntities() (model.WebhookEntities, error) {
+	return wr.GetWebhookEntitiesContext(context.Background())
+}
+
+func (wr WebhookResource) GetWebhookEntitiesContext(ctx context.Context) (model.WebhookEntities, error) {
 	r := client.NewGenericRequest("GetWebhookEntities", client.MethodGET)
 	r.SetVersion(client.V1)
 
-	response, err := wr.client.Call(r)
+	response, err := client.CallContext(ctx, wr.client, r)
 
 	if err != nil {
 		return model.WebhookEntities{}, err
@@ -54,7 +63,7 @@ func (wr WebhookResource) GetWebhookEntities() (model.WebhookEntities, error) {
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return model.WebhookEntities{}, newApiResponseError(errorResponse.HeadObject)
+		return model.WebhookEntities{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -72,10 +81,14 @@ func (wr WebhookResource) GetWebhookEntities() (model.WebhookEntities, error) {
 }
 
 func (wr WebhookResource) GetWebhooks() (model.Webhooks, error) {
+	return wr.GetWebhooksContext(context.Background())
+}
+
+func (wr WebhookResource) GetWebhooksContext(ctx context.Context) (model.Webhooks, error) {
 	r := client.NewGenericRequest("GetWebhooks", client.MethodGET)
 	r.SetVersion(client.V1)
 
-	response, err := wr.client.Call(r)
+	response, err := client.CallContext(ctx, wr.client, r)
 
 	if err != nil {
 		return model.Webhooks{}, err
@@ -84,7 +97,7 @@ func (wr WebhookResource) GetWebhooks() (model.Webhooks, error) {
 	if response.IsError() {
 		errorResponse, _ := response.(client.ErrorResponse)
 
-		return model.Webhooks{}, newApiResponseError(errorResponse.HeadObject)
+		return model.Webhooks{}, newApiResponseError(errorResponse)
 	}
 
 	rawBody := response.GetBody()
@@ -53,8 +53,8 @@ func Test_Get_WebhookEntities_Can_Handle_ErrorResponse(t *testing.T) {
 	webhookEntities, err := resource.GetWebhookEntities()
 
 	expectedError := &ApiResponseError{
-		"E27",
-		"Wrong signature",
+		Code:    "E27",
+		Message: "Wrong signature",
 	}
 
 	if !reflect.DeepEqual(expectedError, err) {
@@ -159,8 +159,8 @@ func Test_Get_Webhooks_Can_Handle_ErrorResponse(t *testing.T) {
 	webhooks, err := resource.GetWebhooks()
 
 	expectedError := &ApiResponseError{
-		"E27",
-		"Wrong signature",
+		Code:    "E27",
+		Message: "Wrong signature",
 	}
 
 	if !reflect.DeepEqual(expectedError, err) {
@@ -263,8 +263,8 @@ func Test_Post_CreateWebhook_Can_Handle_ErrorResponse(t *testing.T) {
 	success, err := resource.CreateWebhook(callbackUrl, webhookEvents)
 
 	expectedError := &ApiResponseError{
-		"E27",
-		"Wrong signature",
+		Code:    "E27",
+		Message: "Wrong signature",
 	}
 
 	if !reflect.DeepEqual(expectedError, err) {